@@ -0,0 +1,47 @@
+package main
+
+import (
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// clickThroughOverrideGrace is how long a manual SetClickThrough holds off the game monitor
+const clickThroughOverrideGrace = 2 * time.Minute
+
+// IsClickThrough reports whether mouse events currently pass through the overlay. Always false
+// on platforms without click-through support.
+func (a *App) IsClickThrough() bool {
+	a.clickMu.Lock()
+	defer a.clickMu.Unlock()
+	return a.clickThrough
+}
+
+// SetClickThrough turns click-through on or off by hand. The game monitor leaves it alone for
+// clickThroughOverrideGrace so it doesn't immediately revert the change.
+func (a *App) SetClickThrough(enable bool) {
+	a.clickMu.Lock()
+	a.clickOverrideUntil = time.Now().Add(clickThroughOverrideGrace)
+	a.clickMu.Unlock()
+	a.applyClickThrough(enable)
+}
+
+// clickThroughOverridden reports whether a manual SetClickThrough is still in its grace period
+func (a *App) clickThroughOverridden() bool {
+	a.clickMu.Lock()
+	defer a.clickMu.Unlock()
+	return time.Now().Before(a.clickOverrideUntil)
+}
+
+// applyClickThrough sets click-through and emits "overlay:click-through" when the state changes
+func (a *App) applyClickThrough(enable bool) {
+	a.clickMu.Lock()
+	before := a.clickThrough
+	a.setOverlayClickThrough(enable)
+	changed := a.clickThrough != before
+	a.clickMu.Unlock()
+
+	if changed && a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "overlay:click-through", enable)
+	}
+}
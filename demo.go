@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"lyrics-overlay/internal/lyrics"
+	"lyrics-overlay/internal/overlay"
+)
+
+// StartDemoPlayback loops sample synced lyrics on the overlay so fonts, colors and sync
+// offset can be tuned without music. Spotify polling is suspended until StopDemoPlayback.
+func (a *App) StartDemoPlayback() error {
+	svc := a.services()
+	if svc.overlay == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+	if a.IsExternalControl() {
+		return fmt.Errorf("demo playback is unavailable under external control")
+	}
+
+	a.demoMu.Lock()
+	defer a.demoMu.Unlock()
+	if a.demoStop != nil {
+		return nil // Already running
+	}
+
+	// Keep the poll loop from replacing the demo track
+	a.demoResumePolling = svc.spotify != nil && svc.spotify.IsPolling()
+	if a.demoResumePolling {
+		svc.spotify.Stop()
+	}
+
+	stop := make(chan struct{})
+	a.demoStop = stop
+	go a.runDemoPlayback(svc.overlay, stop)
+	return nil
+}
+
+// runDemoPlayback restarts the demo track each time it reaches the end, until stopped
+func (a *App) runDemoPlayback(overlaySvc *overlay.Service, stop chan struct{}) {
+	demo := lyrics.NewDemoProvider().SyncedDemo()
+	for {
+		// The overlay extrapolates progress from UpdatedAt, so the lines advance on their own
+		overlaySvc.SetCurrentTrack(&overlay.TrackInfo{
+			ID:        lyrics.DemoTrackID,
+			Name:      "Demo Playback",
+			Artists:   []string{"SpotLy"},
+			Duration:  lyrics.DemoDurationMs,
+			IsPlaying: true,
+			UpdatedAt: time.Now(),
+			Explicit:  true,
+		})
+		overlaySvc.SetLyricsForTrack(lyrics.DemoTrackID, demo)
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Duration(lyrics.DemoDurationMs) * time.Millisecond):
+		}
+	}
+}
+
+// StopDemoPlayback ends demo playback and resumes following Spotify
+func (a *App) StopDemoPlayback() {
+	svc := a.services()
+	a.demoMu.Lock()
+	defer a.demoMu.Unlock()
+	if a.demoStop == nil {
+		return
+	}
+	close(a.demoStop)
+	a.demoStop = nil
+
+	if svc.overlay != nil {
+		svc.overlay.SetCurrentTrack(nil)
+		svc.overlay.SetCurrentLyrics(nil)
+	}
+	if a.demoResumePolling && svc.spotify != nil {
+		svc.spotify.Start()
+	}
+	a.demoResumePolling = false
+}
+
+// IsDemoPlaying reports whether demo playback is running
+func (a *App) IsDemoPlaying() bool {
+	a.demoMu.Lock()
+	defer a.demoMu.Unlock()
+	return a.demoStop != nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	stdruntime "runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"lyrics-overlay/internal/cache"
+	"lyrics-overlay/internal/config"
+	"lyrics-overlay/internal/lyrics"
+	"lyrics-overlay/internal/overlay"
+)
+
+// maxLogLines is how many recent log lines are kept for diagnostics
+const maxLogLines = 200
+
+// logBuffer keeps the most recent log lines in memory; it is installed as an extra log output
+type logBuffer struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// recentLogs receives everything written through the standard logger
+var recentLogs = &logBuffer{}
+
+// Write records each complete line, dropping the oldest beyond maxLogLines
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		b.lines = append(b.lines, line)
+	}
+	if excess := len(b.lines) - maxLogLines; excess > 0 {
+		b.lines = append([]string(nil), b.lines[excess:]...)
+	}
+	return len(p), nil
+}
+
+// Lines returns a copy of the buffered lines, oldest first
+func (b *logBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.lines...)
+}
+
+// diagnosticsBundle is the redacted report written by ExportDiagnostics
+type diagnosticsBundle struct {
+	GeneratedAt  time.Time              `json:"generated_at"`
+	Platform     string                 `json:"platform"`
+	Health       map[string]interface{} `json:"health"`
+	CurrentTrack *overlay.TrackInfo     `json:"current_track"`
+	LyricsSource map[string]interface{} `json:"lyrics_source"`
+	Cache        *cache.CacheStats      `json:"cache"`
+	Config       *config.Config         `json:"config"`
+	Logs         []string               `json:"logs"`
+}
+
+// ExportDiagnostics writes a redacted JSON bundle for bug reports: service health, the current
+// track and lyrics source, cache stats, recent logs and the config without secrets
+func (a *App) ExportDiagnostics(path string) error {
+	svc := a.services()
+	bundle := diagnosticsBundle{
+		GeneratedAt:  time.Now().UTC(),
+		Platform:     stdruntime.GOOS + "/" + stdruntime.GOARCH,
+		Health:       a.GetSystemHealth(),
+		LyricsSource: a.GetCurrentLyricsSource(),
+	}
+	if svc.overlay != nil {
+		if track := svc.overlay.GetCurrentTrack(); track != nil {
+			copied := *track
+			// Device names are often the owner's name ("Alex's iPhone")
+			copied.DeviceID, copied.DeviceName = "", ""
+			bundle.CurrentTrack = &copied
+		}
+	}
+	if a.cache != nil {
+		stats := a.cache.Stats()
+		bundle.Cache = &stats
+	}
+
+	var secrets []string
+	if svc.config != nil {
+		cfg := svc.config.Get()
+		secrets = []string{cfg.SpotifyClientSecret, cfg.Auth.AccessToken, cfg.Auth.RefreshToken, cfg.ProviderContact}
+		redacted, err := svc.config.Redacted()
+		if err != nil {
+			return fmt.Errorf("failed to redact config: %w", err)
+		}
+		redacted.SpotifyClientID = redactValue(redacted.SpotifyClientID)
+		redacted.ProviderContact = redactValue(redacted.ProviderContact)
+		bundle.Config = redacted
+	}
+
+	home, _ := os.UserHomeDir()
+	for _, line := range recentLogs.Lines() {
+		bundle.Logs = append(bundle.Logs, redactLine(line, home, secrets))
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write diagnostics: %w", err)
+	}
+	return nil
+}
+
+// redactValue hides a configured value while showing whether it was set
+func redactValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "[redacted]"
+}
+
+// redactLine removes known secrets and the user's home directory from a log line
+func redactLine(line, home string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			line = strings.ReplaceAll(line, secret, "[redacted]")
+		}
+	}
+	if home != "" && home != string(filepath.Separator) {
+		line = strings.ReplaceAll(line, home, "~")
+	}
+	return line
+}
+
+// spotifyAPIURL is probed to tell network problems apart from token problems
+const spotifyAPIURL = "https://api.spotify.com/v1/"
+
+// RunConnectivityCheck probes each lyrics provider and the Spotify API, and verifies the Spotify
+// token when logged in, so users can tell network, token and matching problems apart. Runs only
+// on demand from the diagnostics screen.
+func (a *App) RunConnectivityCheck() []lyrics.ProviderCheck {
+	svc := a.services()
+	ctx := context.Background()
+	var checks []lyrics.ProviderCheck
+	if svc.lyrics != nil {
+		checks = svc.lyrics.CheckConnectivity(ctx, lyrics.DefaultCheckTimeout)
+	}
+
+	checks = append(checks, lyrics.Check(ctx, "Spotify API", lyrics.DefaultCheckTimeout, func(ctx context.Context) error {
+		return lyrics.PingURL(ctx, nil, spotifyAPIURL)
+	}))
+
+	if svc.auth != nil && svc.auth.IsAuthenticated() {
+		checks = append(checks, lyrics.Check(ctx, "Spotify account", lyrics.DefaultCheckTimeout, func(ctx context.Context) error {
+			client := svc.auth.GetClient()
+			if client == nil {
+				return fmt.Errorf("not logged in")
+			}
+			_, err := client.CurrentUser(ctx)
+			return err
+		}))
+	}
+	return checks
+}
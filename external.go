@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// StartExternalControl lets another source (e.g. an OBS or Stream Deck script) drive the
+// overlay through SetExternalProgress. Spotify polling and demo playback are suspended so
+// live Spotify state can't fight the external input; the current track stays loaded.
+func (a *App) StartExternalControl() error {
+	svc := a.services()
+	if svc.overlay == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+	a.StopDemoPlayback()
+
+	a.externalMu.Lock()
+	defer a.externalMu.Unlock()
+	if a.externalControl {
+		return nil
+	}
+	a.externalControl = true
+	a.externalResumePolling = svc.spotify != nil && svc.spotify.IsPolling()
+	if a.externalResumePolling {
+		svc.spotify.Stop()
+	}
+	return nil
+}
+
+// StopExternalControl hands the overlay back to Spotify polling
+func (a *App) StopExternalControl() {
+	svc := a.services()
+	a.externalMu.Lock()
+	defer a.externalMu.Unlock()
+	if !a.externalControl {
+		return
+	}
+	a.externalControl = false
+
+	if a.externalResumePolling && svc.spotify != nil {
+		svc.spotify.Start()
+	}
+	a.externalResumePolling = false
+}
+
+// IsExternalControl reports whether the overlay is driven by SetExternalProgress
+func (a *App) IsExternalControl() bool {
+	a.externalMu.Lock()
+	defer a.externalMu.Unlock()
+	return a.externalControl
+}
+
+// SetExternalProgress seeks the lyrics of the loaded track to progressMs and sets whether it
+// is playing; the overlay extrapolates from there until the next update. Requires
+// StartExternalControl, and trackID must match the loaded track so a stale source can't
+// move the wrong song.
+func (a *App) SetExternalProgress(trackID string, progressMs int64, isPlaying bool) error {
+	svc := a.services()
+	if svc.overlay == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+	if !a.IsExternalControl() {
+		return fmt.Errorf("external control is off")
+	}
+	current := svc.overlay.GetCurrentTrack()
+	if current == nil {
+		return fmt.Errorf("no track loaded")
+	}
+	if current.ID != trackID {
+		return fmt.Errorf("track %q is not the loaded track", trackID)
+	}
+
+	track := *current
+	track.Progress = max(progressMs, 0)
+	if track.Duration > 0 {
+		track.Progress = min(track.Progress, track.Duration)
+	}
+	track.IsPlaying = isPlaying
+	track.UpdatedAt = time.Now()
+	svc.overlay.SetCurrentTrack(&track)
+	return nil
+}
@@ -4,10 +4,17 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os/exec"
 	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/zmb3/spotify/v2"
@@ -39,11 +46,27 @@ func openBrowser(url string) error {
 type Service struct {
 	config        *config.Service
 	authenticator *spotifyauth.Authenticator
-	client        *spotify.Client
+	client        atomic.Pointer[spotify.Client] // Read with currentClient; swapped by refreshes from any goroutine
 	server        *http.Server
-	state         string
+	needsReauth   atomic.Bool // Set when Spotify rejects a request for missing scopes
+
+	stateMu sync.Mutex
+	state   string // OAuth state expected by the callback; regenerated per flow, cleared once used
+
+	countryMu sync.RWMutex
+	country   string // Account country from the user profile, "" until known
+
+	refreshMu     sync.Mutex    // Serializes token refreshes between GetClient and the refresher
+	refresherStop chan struct{} // Closed to stop the background refresher; nil when not running
 }
 
+const (
+	// tokenRefreshLead is how long before expiry the access token is refreshed
+	tokenRefreshLead = 5 * time.Minute
+	// refresherInterval is how often the background refresher checks the token
+	refresherInterval = time.Minute
+)
+
 // New creates a new auth service
 func New(configSvc *config.Service) (*Service, error) {
 	cfg := configSvc.Get()
@@ -52,18 +75,9 @@ func New(configSvc *config.Service) (*Service, error) {
 		return nil, fmt.Errorf("Spotify client ID and secret must be configured")
 	}
 
-	// Generate random state for OAuth security
-	state, err := generateRandomState()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate OAuth state: %w", err)
-	}
-
 	auth := spotifyauth.New(
 		spotifyauth.WithRedirectURL(cfg.RedirectURI),
-		spotifyauth.WithScopes(
-			spotifyauth.ScopeUserReadCurrentlyPlaying,
-			spotifyauth.ScopeUserReadPlaybackState,
-		),
+		spotifyauth.WithScopes(RequiredScopes(cfg)...),
 		spotifyauth.WithClientID(cfg.SpotifyClientID),
 		spotifyauth.WithClientSecret(cfg.SpotifyClientSecret),
 	)
@@ -71,17 +85,101 @@ func New(configSvc *config.Service) (*Service, error) {
 	service := &Service{
 		config:        configSvc,
 		authenticator: auth,
-		state:         state,
+	}
+	// Generate random state for OAuth security
+	if _, err := service.rotateState(); err != nil {
+		return nil, err
 	}
 
 	// If we have existing tokens, try to create a client
 	if cfg.Auth.AccessToken != "" {
 		service.createClientFromStoredTokens()
+		// A token granted before a feature was enabled won't cover its scopes
+		if len(service.MissingScopes()) > 0 || service.ScopesChanged() {
+			service.needsReauth.Store(true)
+		}
 	}
+	service.startRefresher()
 
 	return service, nil
 }
 
+// RequiredScopes returns the OAuth scopes needed for the enabled features
+func RequiredScopes(cfg *config.Config) []string {
+	scopes := []string{
+		spotifyauth.ScopeUserReadCurrentlyPlaying,
+		spotifyauth.ScopeUserReadPlaybackState,
+	}
+	if cfg.Features.PlaybackControl {
+		scopes = append(scopes, spotifyauth.ScopeUserModifyPlaybackState)
+	}
+	if cfg.Features.RecentlyPlayed {
+		scopes = append(scopes, spotifyauth.ScopeUserReadRecentlyPlayed)
+	}
+	return scopes
+}
+
+// GetGrantedScopes returns the scopes granted with the stored token
+func (s *Service) GetGrantedScopes() []string {
+	return strings.Fields(s.config.Get().Auth.Scope)
+}
+
+// MissingScopes returns required scopes not covered by the stored token.
+// Tokens saved before scopes were recorded are assumed to be sufficient.
+func (s *Service) MissingScopes() []string {
+	granted := s.GetGrantedScopes()
+	if len(granted) == 0 {
+		return nil
+	}
+
+	grantedSet := make(map[string]struct{}, len(granted))
+	for _, scope := range granted {
+		grantedSet[scope] = struct{}{}
+	}
+
+	missing := make([]string, 0)
+	for _, scope := range RequiredScopes(s.config.Get()) {
+		if _, ok := grantedSet[scope]; !ok {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// ScopesChanged reports whether the app now requires a different scope set than the one the
+// user authorized. Tokens saved before requested scopes were recorded are not flagged.
+func (s *Service) ScopesChanged() bool {
+	requested := strings.Fields(s.config.Get().Auth.RequestedScopes)
+	if len(requested) == 0 {
+		return false
+	}
+
+	required := RequiredScopes(s.config.Get())
+	if len(requested) != len(required) {
+		return true
+	}
+	requestedSet := make(map[string]struct{}, len(requested))
+	for _, scope := range requested {
+		requestedSet[scope] = struct{}{}
+	}
+	for _, scope := range required {
+		if _, ok := requestedSet[scope]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkScopeError records that Spotify rejected a request, likely for missing scopes
+func (s *Service) MarkScopeError() {
+	s.needsReauth.Store(true)
+}
+
+// NeedsReauth reports whether the user should re-authenticate to grant required scopes
+func (s *Service) NeedsReauth() bool {
+	return s.needsReauth.Load()
+}
+
 // generateRandomState generates a random state string for OAuth security
 func generateRandomState() (string, error) {
 	b := make([]byte, 32)
@@ -104,42 +202,164 @@ func (s *Service) createClientFromStoredTokens() {
 	}
 
 	client := spotify.New(s.authenticator.Client(context.Background(), token))
-	s.client = client
+	s.client.Store(client)
 
 	// Test if token is still valid
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if _, err := client.CurrentUser(ctx); err != nil {
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
 		// Token might be expired, try to refresh
 		if s.refreshToken() != nil {
 			// Refresh failed, clear stored tokens
 			s.clearTokens()
+			return
 		}
+		go s.fetchCountry()
+		return
 	}
+	s.setCountry(user.Country)
+}
+
+// fetchCountry reads the account country from the user profile
+func (s *Service) fetchCountry() {
+	client := s.currentClient()
+	if client == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if user, err := client.CurrentUser(ctx); err == nil {
+		s.setCountry(user.Country)
+	}
+}
+
+// setCountry records the account country
+func (s *Service) setCountry(country string) {
+	s.countryMu.Lock()
+	defer s.countryMu.Unlock()
+	s.country = strings.ToUpper(strings.TrimSpace(country))
+}
+
+// Country returns the account's ISO 3166-1 alpha-2 country code, or "" when unknown
+func (s *Service) Country() string {
+	s.countryMu.RLock()
+	defer s.countryMu.RUnlock()
+	return s.country
 }
 
 // IsAuthenticated checks if the user is authenticated
 func (s *Service) IsAuthenticated() bool {
-	return s.client != nil
+	return s.currentClient() != nil
+}
+
+// currentClient returns the Spotify client, or nil when not authenticated. Unlike GetClient it
+// never refreshes the token.
+func (s *Service) currentClient() *spotify.Client {
+	return s.client.Load()
 }
 
 // GetClient returns the authenticated Spotify client
 func (s *Service) GetClient() *spotify.Client {
-	if s.client == nil {
+	if s.currentClient() == nil {
 		return nil
 	}
 
-	// Check if token needs refresh
-	cfg := s.config.Get()
-	if time.Now().Unix() >= cfg.Auth.ExpiresAt-300 { // Refresh 5 minutes before expiry
-		if err := s.refreshToken(); err != nil {
+	// Check if token needs refresh. Keep the tokens when Spotify couldn't be reached (e.g. the
+	// network is still coming back after sleep); the next call retries.
+	if err := s.refreshIfExpiring(); err != nil {
+		if !isTransientRefreshError(err) {
 			s.clearTokens()
-			return nil
 		}
+		return nil
+	}
+
+	return s.currentClient()
+}
+
+// RefreshAfterResume refreshes the token right after the machine wakes from sleep, when it has
+// likely expired, so polling recovers without waiting for the background refresher. Failures
+// keep the stored tokens, like transient GetClient failures.
+func (s *Service) RefreshAfterResume() error {
+	if s.currentClient() == nil {
+		return nil
+	}
+	return s.refreshIfExpiring()
+}
+
+// isTransientRefreshError reports whether a refresh failed to reach Spotify at all, rather than
+// being rejected (e.g. a revoked refresh token)
+func isTransientRefreshError(err error) bool {
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// TokenExpiresIn returns how long the access token remains valid (0 when not authenticated)
+func (s *Service) TokenExpiresIn() time.Duration {
+	expiresAt := s.config.Get().Auth.ExpiresAt
+	if s.currentClient() == nil || expiresAt == 0 {
+		return 0
+	}
+	if remaining := time.Until(time.Unix(expiresAt, 0)); remaining > 0 {
+		return remaining
 	}
+	return 0
+}
+
+// refreshIfExpiring refreshes the token if it expires within tokenRefreshLead. The check runs
+// under refreshMu so on-access and background refreshes don't both spend the refresh token.
+func (s *Service) refreshIfExpiring() error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
 
-	return s.client
+	expiresAt := time.Unix(s.config.Get().Auth.ExpiresAt, 0)
+	if time.Until(expiresAt) > tokenRefreshLead {
+		return nil
+	}
+	return s.refreshToken()
+}
+
+// startRefresher starts the background refresher, which keeps the token fresh while nothing
+// is calling GetClient (e.g. playback paused for a long time)
+func (s *Service) startRefresher() {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if s.refresherStop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	s.refresherStop = stop
+
+	go func() {
+		ticker := time.NewTicker(refresherInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if s.currentClient() == nil {
+					continue
+				}
+				// Transient failures are retried on the next tick; GetClient handles hard failures
+				if err := s.refreshIfExpiring(); err != nil {
+					log.Printf("Background token refresh failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// stopRefresher stops the background refresher, if running
+func (s *Service) stopRefresher() {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if s.refresherStop != nil {
+		close(s.refresherStop)
+		s.refresherStop = nil
+	}
 }
 
 // StartOAuthFlow starts the OAuth2 authentication flow
@@ -154,8 +374,12 @@ func (s *Service) StartOAuthFlow() error {
 		return fmt.Errorf("failed to start callback server: %w", err)
 	}
 
-	// Generate the authorization URL
-	authURL := s.authenticator.AuthURL(s.state)
+	// Generate the authorization URL with a fresh state, so URLs from earlier attempts stop working
+	state, err := s.rotateState()
+	if err != nil {
+		return err
+	}
+	authURL := s.authenticator.AuthURL(state)
 
 	// Open the browser automatically
 	if err := openBrowser(authURL); err != nil {
@@ -177,7 +401,7 @@ func (s *Service) startCallbackServer(port int) error {
 
 	go func() {
 		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Printf("Callback server error: %v\n", err)
+			log.Printf("Callback server error: %v", err)
 		}
 	}()
 
@@ -195,8 +419,7 @@ func (s *Service) handleCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify state
-	state := r.URL.Query().Get("state")
-	if state != s.state {
+	if !s.consumeState(r.URL.Query().Get("state")) {
 		http.Error(w, "Invalid state parameter", http.StatusBadRequest)
 		return
 	}
@@ -210,13 +433,17 @@ func (s *Service) handleCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Save tokens
-	if err := s.saveTokens(token); err != nil {
+	requested := strings.Join(RequiredScopes(s.config.Get()), " ")
+	if err := s.saveTokens(token, requested); err != nil {
 		http.Error(w, fmt.Sprintf("Failed to save tokens: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	// Create Spotify client
-	s.client = spotify.New(s.authenticator.Client(context.Background(), token))
+	s.client.Store(spotify.New(s.authenticator.Client(context.Background(), token)))
+	s.needsReauth.Store(false)
+	s.startRefresher()
+	go s.fetchCountry()
 
 	// Send success response
 	fmt.Fprintf(w, `
@@ -248,14 +475,24 @@ func (s *Service) stopCallbackServer() {
 	}
 }
 
-// saveTokens saves OAuth tokens to configuration
-func (s *Service) saveTokens(token *oauth2.Token) error {
+// saveTokens saves OAuth tokens to configuration along with the scopes requested for them
+func (s *Service) saveTokens(token *oauth2.Token, requestedScopes string) error {
 	cfg := s.config.Get()
+
+	// Spotify returns the granted scopes with the token; refreshes may omit them
+	scope, _ := token.Extra("scope").(string)
+	if scope == "" {
+		scope = cfg.Auth.Scope
+	}
+
 	cfg.Auth = config.AuthConfig{
 		AccessToken:  token.AccessToken,
 		RefreshToken: token.RefreshToken,
 		TokenType:    token.TokenType,
 		ExpiresAt:    token.Expiry.Unix(),
+		Scope:        scope,
+
+		RequestedScopes: requestedScopes,
 	}
 
 	return s.config.UpdateAuth(cfg.Auth)
@@ -263,7 +500,7 @@ func (s *Service) saveTokens(token *oauth2.Token) error {
 
 // refreshToken refreshes the OAuth token
 func (s *Service) refreshToken() error {
-	if s.client == nil {
+	if s.currentClient() == nil {
 		return fmt.Errorf("no client available")
 	}
 
@@ -286,12 +523,12 @@ func (s *Service) refreshToken() error {
 	}
 
 	// Save the new token
-	if err := s.saveTokens(newToken); err != nil {
+	if err := s.saveTokens(newToken, cfg.Auth.RequestedScopes); err != nil {
 		return fmt.Errorf("failed to save refreshed token: %w", err)
 	}
 
 	// Update the client
-	s.client = spotify.New(s.authenticator.Client(context.Background(), newToken))
+	s.client.Store(spotify.New(s.authenticator.Client(context.Background(), newToken)))
 
 	return nil
 }
@@ -301,16 +538,58 @@ func (s *Service) clearTokens() {
 	cfg := s.config.Get()
 	cfg.Auth = config.AuthConfig{}
 	_ = s.config.UpdateAuth(cfg.Auth)
-	s.client = nil
+	s.client.Store(nil)
+	s.setCountry("")
 }
 
 // Logout clears authentication and logs out the user
 func (s *Service) Logout() {
+	s.stopRefresher()
 	s.clearTokens()
 	s.stopCallbackServer()
 }
 
-// GetAuthURL returns the OAuth authorization URL
+// Close stops the background refresher and any pending OAuth callback server while keeping stored tokens
+func (s *Service) Close() {
+	s.stopRefresher()
+	s.stopCallbackServer()
+}
+
+// GetAuthURL returns the OAuth authorization URL for the current flow
 func (s *Service) GetAuthURL() string {
-	return s.authenticator.AuthURL(s.state)
+	s.stateMu.Lock()
+	state := s.state
+	s.stateMu.Unlock()
+	if state == "" {
+		// The last flow completed; a new URL needs a new state
+		var err error
+		if state, err = s.rotateState(); err != nil {
+			log.Printf("Failed to generate OAuth state: %v", err)
+		}
+	}
+	return s.authenticator.AuthURL(state)
+}
+
+// rotateState replaces the expected OAuth state with a new random one and returns it
+func (s *Service) rotateState() (string, error) {
+	state, err := generateRandomState()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+	s.stateMu.Lock()
+	s.state = state
+	s.stateMu.Unlock()
+	return state, nil
+}
+
+// consumeState reports whether state is the one expected by the callback, clearing it on a
+// match so the same authorization response can't be replayed
+func (s *Service) consumeState(state string) bool {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	if state == "" || state != s.state {
+		return false
+	}
+	s.state = ""
+	return true
 }
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+	"golang.org/x/oauth2"
+
+	"lyrics-overlay/internal/config"
+)
+
+// newTestService creates an auth service with credentials but no stored tokens
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	configSvc, err := config.NewWithPath(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("config.NewWithPath failed: %v", err)
+	}
+	configSvc.Get().SpotifyClientID = "client-id"
+	configSvc.Get().SpotifyClientSecret = "client-secret"
+
+	service, err := New(configSvc)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(service.Close)
+	return service
+}
+
+func TestTokenExpiresIn(t *testing.T) {
+	s := newTestService(t)
+	if got := s.TokenExpiresIn(); got != 0 {
+		t.Errorf("TokenExpiresIn without a client = %v; want 0", got)
+	}
+
+	s.client.Store(&spotify.Client{})
+	s.config.Get().Auth.ExpiresAt = time.Now().Add(30 * time.Minute).Unix()
+	if got := s.TokenExpiresIn(); got < 29*time.Minute || got > 30*time.Minute {
+		t.Errorf("TokenExpiresIn = %v; want about 30m", got)
+	}
+
+	s.config.Get().Auth.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	if got := s.TokenExpiresIn(); got != 0 {
+		t.Errorf("TokenExpiresIn for an expired token = %v; want 0", got)
+	}
+}
+
+func TestRefreshIfExpiring_FreshToken(t *testing.T) {
+	s := newTestService(t)
+	s.config.Get().Auth.ExpiresAt = time.Now().Add(time.Hour).Unix()
+
+	// A token well within its lifetime is left alone (refreshing would fail without a client)
+	if err := s.refreshIfExpiring(); err != nil {
+		t.Errorf("refreshIfExpiring = %v; want no refresh", err)
+	}
+}
+
+func TestRefresherStopsOnLogout(t *testing.T) {
+	s := newTestService(t)
+	if s.refresherStop == nil {
+		t.Fatal("Expected the refresher to start with the service")
+	}
+
+	s.Logout()
+	if s.refresherStop != nil {
+		t.Error("Expected Logout to stop the refresher")
+	}
+	// Stopping twice is harmless
+	s.Close()
+}
+
+func TestScopesChanged(t *testing.T) {
+	s := newTestService(t)
+	if s.ScopesChanged() {
+		t.Error("Tokens without recorded scopes should not force re-auth")
+	}
+
+	s.config.Get().Auth.RequestedScopes = strings.Join(RequiredScopes(s.config.Get()), " ")
+	if s.ScopesChanged() {
+		t.Error("Same scope set should not force re-auth")
+	}
+
+	s.config.Get().Features.RecentlyPlayed = true
+	if !s.ScopesChanged() {
+		t.Error("Enabling a feature with new scopes should force re-auth")
+	}
+}
+
+func TestHandleCallback_RejectsStaleState(t *testing.T) {
+	s := newTestService(t)
+	stale := s.state
+
+	if _, err := s.rotateState(); err != nil {
+		t.Fatalf("rotateState failed: %v", err)
+	}
+	if s.state == stale {
+		t.Fatal("Expected a new state for the new flow")
+	}
+	if !strings.Contains(s.GetAuthURL(), "state="+url.QueryEscape(s.state)) {
+		t.Errorf("GetAuthURL should use the current state, got %s", s.GetAuthURL())
+	}
+
+	recorder := httptest.NewRecorder()
+	s.handleCallback(recorder, httptest.NewRequest(http.MethodGet, "/callback?code=abc&state="+url.QueryEscape(stale), nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Callback with a stale state returned %d; want %d", recorder.Code, http.StatusBadRequest)
+	}
+
+	// A matching state is single-use
+	current := s.state
+	if !s.consumeState(current) || s.consumeState(current) {
+		t.Error("Expected the current state to validate exactly once")
+	}
+}
+
+func TestIsTransientRefreshError(t *testing.T) {
+	offline := fmt.Errorf("failed to refresh token: %w", &url.Error{Op: "Post", URL: "https://accounts.spotify.com/api/token", Err: errors.New("dial tcp: no such host")})
+	if !isTransientRefreshError(offline) {
+		t.Error("Expected an unreachable token endpoint to be transient")
+	}
+	revoked := fmt.Errorf("failed to refresh token: %w", &oauth2.RetrieveError{ErrorCode: "invalid_grant"})
+	if isTransientRefreshError(revoked) {
+		t.Error("Expected a rejected refresh token not to be transient")
+	}
+
+	// Nothing to refresh without a client
+	if err := newTestService(t).RefreshAfterResume(); err != nil {
+		t.Errorf("RefreshAfterResume without a client = %v; want nil", err)
+	}
+}
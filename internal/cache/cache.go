@@ -2,9 +2,15 @@ package cache
 
 import (
 	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
+	"lyrics-overlay/internal/config"
 	"lyrics-overlay/internal/overlay"
 )
 
@@ -17,14 +23,29 @@ type Service struct {
 	lruList     *list.List               // LRU list for eviction
 	trackToElem map[string]*list.Element // Map track ID to list element
 	keyToElem   map[string]*list.Element // Map cache key to list element
+	pinned      map[string]*cacheEntry   // Pinned track entries; never expire or get evicted
+	maxPinned   int
 }
 
+// DefaultMaxPinned is the default budget for pinned entries, separate from the LRU size
+const DefaultMaxPinned = 500
+
 // cacheEntry holds cached lyrics data with metadata
 type cacheEntry struct {
 	lyrics    *overlay.LyricsData
 	trackID   string
 	cacheKey  string
 	timestamp time.Time
+	pinned    bool
+	longLived bool // Preloaded offline entries that don't expire, though LRU can still evict them
+}
+
+// EntryTTL is how long lyrics stay cached, unless pinned or long-lived
+const EntryTTL = 24 * time.Hour
+
+// expired reports whether the entry has outlived EntryTTL at now
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.pinned && !e.longLived && now.Sub(e.timestamp) > EntryTTL
 }
 
 // New creates a new cache service
@@ -40,21 +61,24 @@ func New(maxSize int) *Service {
 		lruList:     list.New(),
 		trackToElem: make(map[string]*list.Element),
 		keyToElem:   make(map[string]*list.Element),
+		pinned:      make(map[string]*cacheEntry),
+		maxPinned:   DefaultMaxPinned,
 	}
 }
 
 // GetByTrackID retrieves lyrics by Spotify track ID
 func (s *Service) GetByTrackID(trackID string) *overlay.LyricsData {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	// Write lock: a hit moves the entry in the LRU list and an expired one is removed
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	entry, exists := s.trackCache[trackID]
 	if !exists {
 		return nil
 	}
 
-	// Check if entry is still valid (24 hours)
-	if time.Since(entry.timestamp) > 24*time.Hour {
+	// Check if entry is still valid; pinned entries don't expire
+	if entry.expired(time.Now()) {
 		// Entry is stale, remove it
 		s.removeEntryUnsafe(entry)
 		return nil
@@ -70,16 +94,17 @@ func (s *Service) GetByTrackID(trackID string) *overlay.LyricsData {
 
 // GetByKey retrieves lyrics by normalized cache key
 func (s *Service) GetByKey(cacheKey string) *overlay.LyricsData {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	// Write lock: a hit moves the entry in the LRU list and an expired one is removed
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	entry, exists := s.keyCache[cacheKey]
 	if !exists {
 		return nil
 	}
 
-	// Check if entry is still valid (24 hours)
-	if time.Since(entry.timestamp) > 24*time.Hour {
+	// Check if entry is still valid; long-lived entries don't expire
+	if entry.expired(time.Now()) {
 		// Entry is stale, remove it
 		s.removeEntryUnsafe(entry)
 		return nil
@@ -131,6 +156,17 @@ func (s *Service) SetByTrackID(trackID string, lyrics *overlay.LyricsData) {
 
 // SetByKey caches lyrics by normalized cache key
 func (s *Service) SetByKey(cacheKey string, lyrics *overlay.LyricsData) {
+	s.setByKey(cacheKey, lyrics, false)
+}
+
+// SetByKeyLongLived caches lyrics by normalized cache key without the 24h expiry,
+// for offline datasets loaded at startup
+func (s *Service) SetByKeyLongLived(cacheKey string, lyrics *overlay.LyricsData) {
+	s.setByKey(cacheKey, lyrics, true)
+}
+
+// setByKey caches lyrics by normalized cache key
+func (s *Service) setByKey(cacheKey string, lyrics *overlay.LyricsData, longLived bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -139,6 +175,7 @@ func (s *Service) SetByKey(cacheKey string, lyrics *overlay.LyricsData) {
 		// Update existing entry
 		existingEntry.lyrics = lyrics
 		existingEntry.timestamp = time.Now()
+		existingEntry.longLived = longLived
 
 		// Move to front
 		if elem, exists := s.keyToElem[cacheKey]; exists {
@@ -152,6 +189,7 @@ func (s *Service) SetByKey(cacheKey string, lyrics *overlay.LyricsData) {
 		lyrics:    lyrics,
 		cacheKey:  cacheKey,
 		timestamp: time.Now(),
+		longLived: longLived,
 	}
 
 	// Add to cache maps
@@ -182,6 +220,9 @@ func (s *Service) removeEntryUnsafe(entry *cacheEntry) {
 	// Remove from track cache
 	if entry.trackID != "" {
 		delete(s.trackCache, entry.trackID)
+		if entry.pinned {
+			delete(s.pinned, entry.trackID)
+		}
 		if elem, exists := s.trackToElem[entry.trackID]; exists {
 			s.lruList.Remove(elem)
 			delete(s.trackToElem, entry.trackID)
@@ -198,6 +239,172 @@ func (s *Service) removeEntryUnsafe(entry *cacheEntry) {
 	}
 }
 
+// RemoveByTrackID removes the entry for a track along with key entries holding the same lyrics
+func (s *Service) RemoveByTrackID(trackID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.trackCache[trackID]
+	if !exists {
+		return false
+	}
+
+	// The same lyrics are usually cached by normalized key too; drop those so they aren't served again
+	for _, keyEntry := range s.keyCache {
+		if keyEntry.lyrics == entry.lyrics {
+			s.removeEntryUnsafe(keyEntry)
+		}
+	}
+	s.removeEntryUnsafe(entry)
+
+	return true
+}
+
+// ListEntries returns a snapshot of cached entries: unpinned ones most recently used first,
+// followed by the pinned ones (which aren't in the LRU order) by track ID
+func (s *Service) ListEntries() []CacheEntryInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]CacheEntryInfo, 0, s.lruList.Len()+len(s.pinned))
+	for elem := s.lruList.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, entryInfo(elem.Value.(*cacheEntry)))
+	}
+	pinned := make([]CacheEntryInfo, 0, len(s.pinned))
+	for _, entry := range s.pinned {
+		pinned = append(pinned, entryInfo(entry))
+	}
+	sort.Slice(pinned, func(i, j int) bool { return pinned[i].TrackID < pinned[j].TrackID })
+
+	return append(entries, pinned...)
+}
+
+// entryInfo describes a cache entry for diagnostics
+func entryInfo(entry *cacheEntry) CacheEntryInfo {
+	info := CacheEntryInfo{
+		TrackID:    entry.trackID,
+		CacheKey:   entry.cacheKey,
+		AgeSeconds: int64(time.Since(entry.timestamp).Seconds()),
+		Pinned:     entry.pinned,
+	}
+	if entry.lyrics != nil {
+		info.Source = entry.lyrics.Source
+		info.Synced = entry.lyrics.IsSynced
+		info.LineCount = len(entry.lyrics.Lines)
+	}
+	return info
+}
+
+// Pin marks a track's cached lyrics as immune to eviction and expiry. Pins have their own
+// budget (DefaultMaxPinned) rather than counting toward the LRU size.
+func (s *Service) Pin(trackID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.trackCache[trackID]
+	if !exists {
+		return fmt.Errorf("no cache entry for track %s", trackID)
+	}
+	if entry.pinned {
+		return nil
+	}
+	if len(s.pinned) >= s.maxPinned {
+		return fmt.Errorf("pinned lyrics limit (%d) reached", s.maxPinned)
+	}
+
+	// Move the entry out of the LRU list so it no longer competes for space
+	if elem, exists := s.trackToElem[trackID]; exists {
+		s.lruList.Remove(elem)
+		delete(s.trackToElem, trackID)
+	}
+	entry.pinned = true
+	s.pinned[trackID] = entry
+	return nil
+}
+
+// Unpin returns a pinned entry to the LRU cache, where it can be evicted and expire again
+func (s *Service) Unpin(trackID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.pinned[trackID]
+	if !exists {
+		return false
+	}
+	delete(s.pinned, trackID)
+	entry.pinned = false
+	entry.timestamp = time.Now()
+	s.trackToElem[trackID] = s.lruList.PushFront(entry)
+	s.enforceMaxSize()
+	return true
+}
+
+// IsPinned reports whether the track's lyrics are pinned
+func (s *Service) IsPinned(trackID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, exists := s.pinned[trackID]
+	return exists
+}
+
+// pinnedRecord is the on-disk form of a pinned entry
+type pinnedRecord struct {
+	TrackID string              `json:"track_id"`
+	Lyrics  *overlay.LyricsData `json:"lyrics"`
+}
+
+// SavePinned writes the pinned entries to path so they survive restarts
+func (s *Service) SavePinned(path string) error {
+	s.mu.RLock()
+	records := make([]pinnedRecord, 0, len(s.pinned))
+	for trackID, entry := range s.pinned {
+		records = append(records, pinnedRecord{TrackID: trackID, Lyrics: entry.lyrics})
+	}
+	s.mu.RUnlock()
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(path, data)
+}
+
+// LoadPinned restores pinned entries saved by SavePinned; a missing file is not an error
+func (s *Service) LoadPinned(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read pinned lyrics: %w", err)
+	}
+
+	var records []pinnedRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("failed to parse pinned lyrics: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range records {
+		if record.TrackID == "" || record.Lyrics == nil || len(s.pinned) >= s.maxPinned {
+			continue
+		}
+		if existing, exists := s.trackCache[record.TrackID]; exists {
+			s.removeEntryUnsafe(existing)
+		}
+		entry := &cacheEntry{
+			lyrics:    record.Lyrics,
+			trackID:   record.TrackID,
+			timestamp: time.Now(),
+			pinned:    true,
+		}
+		s.trackCache[record.TrackID] = entry
+		s.pinned[record.TrackID] = entry
+	}
+	return nil
+}
+
 // Clear removes all entries from the cache
 func (s *Service) Clear() {
 	s.mu.Lock()
@@ -208,6 +415,11 @@ func (s *Service) Clear() {
 	s.lruList = list.New()
 	s.trackToElem = make(map[string]*list.Element)
 	s.keyToElem = make(map[string]*list.Element)
+
+	// Pinned lyrics are kept deliberately, so they survive clearing the cache
+	for trackID, entry := range s.pinned {
+		s.trackCache[trackID] = entry
+	}
 }
 
 // Size returns the current cache size
@@ -227,6 +439,8 @@ func (s *Service) Stats() CacheStats {
 		MaxSize:      s.maxSize,
 		TrackEntries: len(s.trackCache),
 		KeyEntries:   len(s.keyCache),
+		Pinned:       len(s.pinned),
+		MaxPinned:    s.maxPinned,
 	}
 }
 
@@ -236,4 +450,18 @@ type CacheStats struct {
 	MaxSize      int `json:"max_size"`
 	TrackEntries int `json:"track_entries"`
 	KeyEntries   int `json:"key_entries"`
+	Pinned       int `json:"pinned"`
+	MaxPinned    int `json:"max_pinned"`
+	Pruned       int `json:"pruned,omitempty"` // Entries removed by the prune that produced these stats
+}
+
+// CacheEntryInfo describes a single cache entry for diagnostics
+type CacheEntryInfo struct {
+	TrackID    string `json:"track_id,omitempty"`
+	CacheKey   string `json:"cache_key,omitempty"`
+	Source     string `json:"source"`
+	Synced     bool   `json:"synced"`
+	LineCount  int    `json:"line_count"`
+	AgeSeconds int64  `json:"age_seconds"`
+	Pinned     bool   `json:"pinned"`
 }
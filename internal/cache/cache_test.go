@@ -1,7 +1,10 @@
 package cache
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"lyrics-overlay/internal/overlay"
 )
@@ -205,3 +208,204 @@ func TestService_Stats(t *testing.T) {
 		t.Errorf("Expected 1 key entry, got %d", stats.KeyEntries)
 	}
 }
+
+func TestService_RemoveByTrackID(t *testing.T) {
+	c := New(10)
+
+	bad := &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: "wrong"}}}
+	other := &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: "other"}}}
+
+	c.SetByTrackID("track1", bad)
+	c.SetByKey("artist|title", bad)
+	c.SetByTrackID("track2", other)
+
+	if !c.RemoveByTrackID("track1") {
+		t.Fatal("Expected RemoveByTrackID to report removal")
+	}
+
+	if got := c.GetByTrackID("track1"); got != nil {
+		t.Error("Expected track1 to be removed")
+	}
+	if got := c.GetByKey("artist|title"); got != nil {
+		t.Error("Expected key entry with the same lyrics to be removed")
+	}
+	if got := c.GetByTrackID("track2"); got == nil {
+		t.Error("Expected track2 to remain")
+	}
+
+	if c.RemoveByTrackID("missing") {
+		t.Error("Expected RemoveByTrackID to report nothing removed for unknown track")
+	}
+}
+
+func TestService_ListEntries(t *testing.T) {
+	c := New(10)
+
+	c.SetByTrackID("track1", &overlay.LyricsData{
+		Source:   "LRCLIB",
+		IsSynced: true,
+		Lines:    []overlay.LyricsLine{{Text: "a"}, {Text: "b"}},
+	})
+	c.SetByKey("artist|title", &overlay.LyricsData{Source: "LRCLIB"})
+
+	entries := c.ListEntries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	// Most recently used first
+	if entries[0].CacheKey != "artist|title" {
+		t.Errorf("Expected key entry first, got %+v", entries[0])
+	}
+	if entries[1].TrackID != "track1" || !entries[1].Synced || entries[1].LineCount != 2 {
+		t.Errorf("Unexpected track entry: %+v", entries[1])
+	}
+}
+
+func TestService_ListEntriesPinnedLast(t *testing.T) {
+	c := New(10)
+	for _, id := range []string{"pinned-b", "pinned-a", "recent"} {
+		c.SetByTrackID(id, &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: id}}})
+	}
+	for _, id := range []string{"pinned-b", "pinned-a"} {
+		if err := c.Pin(id); err != nil {
+			t.Fatalf("Pin(%s) failed: %v", id, err)
+		}
+	}
+
+	entries := c.ListEntries()
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.TrackID)
+	}
+	want := []string{"recent", "pinned-a", "pinned-b"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ListEntries order = %v; want %v (LRU entries, then pinned by track ID)", got, want)
+	}
+	if !entries[1].Pinned || !entries[2].Pinned || entries[0].Pinned {
+		t.Errorf("Unexpected pinned flags: %+v", entries)
+	}
+}
+
+func TestService_PinSurvivesEviction(t *testing.T) {
+	c := New(2)
+	pinned := &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: "pinned"}}}
+	c.SetByTrackID("pinned", pinned)
+	if err := c.Pin("pinned"); err != nil {
+		t.Fatalf("Pin failed: %v", err)
+	}
+
+	for _, id := range []string{"a", "b", "c"} {
+		c.SetByTrackID(id, &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: id}}})
+	}
+
+	if got := c.GetByTrackID("pinned"); got != pinned {
+		t.Error("Expected pinned entry to survive LRU eviction")
+	}
+	if c.Size() != 2 {
+		t.Errorf("LRU size = %d; want 2 (pins don't count)", c.Size())
+	}
+
+	c.Clear()
+	if got := c.GetByTrackID("pinned"); got != pinned {
+		t.Error("Expected pinned entry to survive Clear")
+	}
+
+	if !c.Unpin("pinned") || c.IsPinned("pinned") {
+		t.Error("Expected Unpin to release the entry")
+	}
+}
+
+func TestService_PinMissingEntry(t *testing.T) {
+	c := New(10)
+	if err := c.Pin("missing"); err == nil {
+		t.Error("Expected an error pinning an uncached track")
+	}
+}
+
+func TestService_PinExpiry(t *testing.T) {
+	c := New(10)
+	c.SetByTrackID("track1", &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: "test"}}})
+	c.Pin("track1")
+	c.trackCache["track1"].timestamp = time.Now().Add(-48 * time.Hour)
+
+	if got := c.GetByTrackID("track1"); got == nil {
+		t.Error("Expected pinned entry to ignore the 24h TTL")
+	}
+}
+
+func TestService_SaveAndLoadPinned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pinned_lyrics.json")
+
+	c := New(10)
+	c.SetByTrackID("track1", &overlay.LyricsData{Source: "Manual", IsSynced: true, Lines: []overlay.LyricsLine{{Text: "fixed", Timestamp: 1000}}})
+	c.SetByTrackID("track2", &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: "other"}}})
+	c.Pin("track1")
+	if err := c.SavePinned(path); err != nil {
+		t.Fatalf("SavePinned failed: %v", err)
+	}
+
+	restored := New(10)
+	if err := restored.LoadPinned(path); err != nil {
+		t.Fatalf("LoadPinned failed: %v", err)
+	}
+	got := restored.GetByTrackID("track1")
+	if got == nil || got.Lines[0].Text != "fixed" || !restored.IsPinned("track1") {
+		t.Errorf("Restored pinned entry = %+v; want the pinned lyrics", got)
+	}
+	if restored.GetByTrackID("track2") != nil {
+		t.Error("Only pinned entries should be persisted")
+	}
+
+	if err := New(10).LoadPinned(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("LoadPinned with no file = %v; want nil", err)
+	}
+}
+
+func TestService_LongLivedSkipsExpiry(t *testing.T) {
+	c := New(10)
+	c.SetByKeyLongLived("artist|song", &overlay.LyricsData{Source: "Dataset", Lines: []overlay.LyricsLine{{Text: "test"}}})
+	c.SetByKey("artist|other", &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: "test"}}})
+	for _, entry := range c.keyCache {
+		entry.timestamp = time.Now().Add(-48 * time.Hour)
+	}
+
+	if c.GetByKey("artist|song") == nil {
+		t.Error("Expected long-lived entry to ignore the 24h TTL")
+	}
+	if c.GetByKey("artist|other") != nil {
+		t.Error("Expected regular entry to expire")
+	}
+}
+
+func TestService_Prune(t *testing.T) {
+	c := New(3)
+	lyrics := &overlay.LyricsData{Source: "Test", Lines: []overlay.LyricsLine{{Text: "test"}}}
+	c.SetByTrackID("stale", lyrics)
+	c.SetByTrackID("pinned", lyrics)
+	c.Pin("pinned")
+	c.SetByKeyLongLived("artist|dataset", lyrics)
+	c.SetByKey("artist|fresh", lyrics)
+	for _, entry := range []*cacheEntry{c.trackCache["stale"], c.trackCache["pinned"], c.keyCache["artist|dataset"]} {
+		entry.timestamp = time.Now().Add(-48 * time.Hour)
+	}
+
+	if removed := c.Prune(); removed != 1 {
+		t.Errorf("Prune removed %d entries; want 1", removed)
+	}
+	if c.trackCache["stale"] != nil {
+		t.Error("Expected the expired entry to be pruned")
+	}
+	if c.GetByTrackID("pinned") == nil || c.GetByKey("artist|dataset") == nil || c.GetByKey("artist|fresh") == nil {
+		t.Error("Expected pinned, long-lived and fresh entries to survive the prune")
+	}
+
+	// Over budget (e.g. after a smaller max size) trims the least recently used entries
+	c.maxSize = 1
+	if removed := c.Prune(); removed != 1 || c.Size() != 1 {
+		t.Errorf("Prune over budget removed %d, size %d; want 1 and 1", removed, c.Size())
+	}
+	if removed := c.Prune(); removed != 0 {
+		t.Errorf("Second prune removed %d entries; want 0", removed)
+	}
+}
@@ -0,0 +1,26 @@
+package cache
+
+import "time"
+
+// DefaultPruneInterval is how often the app prunes the cache when the config doesn't say
+const DefaultPruneInterval = 6 * time.Hour
+
+// Prune drops expired entries and enforces the size budget, returning how many entries were
+// removed. It only walks the in-memory entries (no I/O), so lookups wait briefly at most.
+func (s *Service) Prune() int {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := s.lruList.Len()
+	for elem := s.lruList.Front(); elem != nil; {
+		next := elem.Next() // Removing the entry unlinks elem
+		if entry := elem.Value.(*cacheEntry); entry.expired(now) {
+			s.removeEntryUnsafe(entry)
+		}
+		elem = next
+	}
+	s.enforceMaxSize()
+	return before - s.lruList.Len()
+}
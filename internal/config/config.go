@@ -2,9 +2,17 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Config holds all application configuration
@@ -20,6 +28,69 @@ type Config struct {
 
 	// Auth tokens (persisted locally)
 	Auth AuthConfig `json:"auth"`
+
+	// Optional features that require extra Spotify permissions
+	Features FeatureConfig `json:"features"`
+
+	// Hide the overlay for instrumentals and tracks without lyrics
+	HideWhenNoLyrics bool `json:"hide_when_no_lyrics"`
+
+	// Hide the overlay entirely while one of these apps is focused (matched case-insensitively
+	// against the window title), e.g. screen-sharing tools. Separate from the click-through games.
+	HideForApps []string `json:"hide_for_apps"`
+
+	// Hide the overlay while a full-screen app (e.g. video) is in the foreground; detected games
+	// are left to click-through mode instead
+	HideOnFullscreen bool `json:"hide_on_fullscreen"`
+
+	// Automatic matches with a confidence (0-1) below this wait for the user to confirm them or
+	// pick another candidate; 0 always applies them
+	AutoApplyConfidence float64 `json:"auto_apply_confidence"`
+
+	// Window titles (or executable names) that never trigger click-through, matched
+	// case-insensitively as substrings, e.g. browsers showing a page about a game
+	ClickThroughBlocklist []string `json:"click_through_blocklist"`
+
+	// Show plain lyrics instead of synced ones when the match is fuzzy or its duration differs
+	// from the track, since the timing is then likely off
+	PreferPlainOnLowConfidence bool `json:"prefer_plain_on_low_confidence"`
+
+	// Scroll plain (unsynced) lyrics by spreading lines evenly over the track; approximate
+	AutoAdvancePlain bool `json:"auto_advance_plain"`
+
+	// Drop section headers like "[Chorus]" from plain lyrics instead of showing them
+	HideSectionHeaders bool `json:"hide_section_headers"`
+
+	// Mask profanity in lyrics when Spotify marks the playing track as clean
+	MaskProfanityForClean bool `json:"mask_profanity_for_clean"`
+
+	// Keep a local log of lyrics matches (normalized artist/title only, never uploaded)
+	Telemetry bool `json:"telemetry"`
+
+	// Number of recently played tracks to remember
+	HistorySize int `json:"history_size"`
+
+	// Hours between lyrics cache prunes (expired entries and the size budget); 0 uses the default
+	CachePruneIntervalHours int `json:"cache_prune_interval_hours"`
+
+	// Optional contact (e.g. an email) sent in the User-Agent to lyrics providers
+	ProviderContact string `json:"provider_contact"`
+
+	// Maximum lyrics lookups running at once
+	MaxConcurrentFetches int `json:"max_concurrent_fetches"`
+
+	// API root of a self-hosted LRCLIB instance (e.g. http://localhost:3000/api); empty uses the public one
+	LRCLibBaseURL string `json:"lrclib_base_url,omitempty"`
+
+	// Lyrics providers turned off by the user; providers not listed are enabled
+	DisabledProviders []string `json:"disabled_providers,omitempty"`
+
+	// Per-track lyrics timing offsets in ms keyed by Spotify track ID, overriding Overlay.SyncOffset
+	TrackSyncOffsets map[string]int64 `json:"track_sync_offsets_ms,omitempty"`
+
+	// Lyrics lookup deadlines in ms: per provider (keyed by provider name) and across all providers
+	ProviderTimeouts    map[string]int64 `json:"provider_timeouts_ms"`
+	LyricsLookupTimeout int64            `json:"lyrics_lookup_timeout_ms"`
 }
 
 // OverlayConfig holds overlay window settings
@@ -34,7 +105,34 @@ type OverlayConfig struct {
 	Locked       bool    `json:"locked"`
 	Position     string  `json:"position"` // "top-left", "top-right", "bottom-left", "bottom-right"
 	ResizeLocked bool    `json:"resize_locked"`
-	SyncOffset   int64   `json:"sync_offset"` // Lyrics timing offset in ms (positive = earlier)
+	SyncOffset   int64   `json:"sync_offset"`   // Lyrics timing offset in ms (positive = earlier)
+	ShowProgress bool    `json:"show_progress"` // Show the track position (e.g. 1:23 / 3:45)
+	WordTiming   bool    `json:"word_timing"`   // Report karaoke fill from word timings (enhanced LRC)
+	// Show empty lines (instrumental gaps) as blanks instead of skipping ahead to the next line
+	ShowEmptyLines bool `json:"show_empty_lines"`
+	// Bound progress extrapolation between polls and hold small backwards corrections
+	SmoothProgress bool `json:"smooth_progress"`
+	// Shortest time in ms a synced line stays up before the next one replaces it, for readable
+	// fast sections; lags behind the timestamps by at most a moment (0 = off)
+	MinLineDisplayMs int `json:"min_line_display_ms"`
+	// Seconds to keep showing the last line after playback stops (0 clears immediately)
+	LingerSeconds int `json:"linger_seconds"`
+	// Truncate longer lines at a word boundary with "…" to fit a fixed bar (0 = off)
+	MaxLineChars int `json:"max_line_chars"`
+	// Allow FitOverlayToLyrics to widen the window so lyric lines don't wrap
+	AutoFit bool `json:"auto_fit"`
+	// Corner to move to while a game is detected ("opposite" flips Position; empty stays put).
+	// X/Y keep the normal position, restored when the game closes.
+	InGamePosition string `json:"in_game_position"`
+	// Optional backdrop behind the lyrics: "#RRGGBB" (empty for none) and its opacity (0 = transparent)
+	BackgroundColor   string  `json:"background_color"`
+	BackgroundOpacity float64 `json:"background_opacity"`
+}
+
+// FeatureConfig holds toggles for optional Spotify-backed features
+type FeatureConfig struct {
+	PlaybackControl bool `json:"playback_control"` // Requires user-modify-playback-state
+	RecentlyPlayed  bool `json:"recently_played"`  // Requires user-read-recently-played
 }
 
 // AuthConfig holds OAuth tokens
@@ -43,27 +141,178 @@ type AuthConfig struct {
 	RefreshToken string `json:"refresh_token"`
 	TokenType    string `json:"token_type"`
 	ExpiresAt    int64  `json:"expires_at"`
+	Scope        string `json:"scope"` // Space-separated scopes granted with the token
+	// Space-separated scopes requested when the user authorized; a different set forces re-auth
+	RequestedScopes string `json:"requested_scopes"`
 }
 
+// Limits for user-adjustable overlay appearance
+const (
+	MinOpacity  = 0.1
+	MaxOpacity  = 1.0
+	MinFontSize = 8
+	MaxFontSize = 96
+)
+
+// backgroundColorPattern matches the "#RRGGBB" (or "#RGB") colors accepted for the backdrop
+var backgroundColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Clamp brings opacity, font size and the backdrop into their allowed ranges, returning an
+// error that describes any value that had to be adjusted
+func (o *OverlayConfig) Clamp() error {
+	var problems []string
+	if o.Opacity < MinOpacity || o.Opacity > MaxOpacity {
+		clamped := math.Min(math.Max(o.Opacity, MinOpacity), MaxOpacity)
+		problems = append(problems, fmt.Sprintf("opacity %.2f out of range [%.1f, %.1f], using %.2f", o.Opacity, MinOpacity, MaxOpacity, clamped))
+		o.Opacity = clamped
+	}
+	if o.FontSize < MinFontSize || o.FontSize > MaxFontSize {
+		clamped := min(max(o.FontSize, MinFontSize), MaxFontSize)
+		problems = append(problems, fmt.Sprintf("font size %d out of range [%d, %d], using %d", o.FontSize, MinFontSize, MaxFontSize, clamped))
+		o.FontSize = clamped
+	}
+	if o.BackgroundOpacity < 0 || o.BackgroundOpacity > 1 {
+		clamped := math.Min(math.Max(o.BackgroundOpacity, 0), 1)
+		problems = append(problems, fmt.Sprintf("background opacity %.2f out of range [0, 1], using %.2f", o.BackgroundOpacity, clamped))
+		o.BackgroundOpacity = clamped
+	}
+	if o.MinLineDisplayMs < 0 {
+		problems = append(problems, fmt.Sprintf("minimum line display time %dms is negative, using 0", o.MinLineDisplayMs))
+		o.MinLineDisplayMs = 0
+	}
+	if o.MaxLineChars < 0 {
+		problems = append(problems, fmt.Sprintf("maximum line length %d is negative, using 0 (no limit)", o.MaxLineChars))
+		o.MaxLineChars = 0
+	}
+	if o.BackgroundColor != "" && !backgroundColorPattern.MatchString(o.BackgroundColor) {
+		problems = append(problems, fmt.Sprintf("background color %q is not a #RRGGBB color, using none", o.BackgroundColor))
+		o.BackgroundColor = ""
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid overlay settings: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ErrConfigRecovered is returned by Load when a corrupt config file was reset to defaults
+var ErrConfigRecovered = errors.New("config file was invalid and has been reset to defaults")
+
 // Service manages configuration persistence
 type Service struct {
 	config   *Config
 	filePath string
+	warning  string // Non-fatal problem encountered while loading
+
+	saveMu    sync.Mutex
+	saveTimer *time.Timer // Pending debounced save, nil when none
+
+	// Guards the config pointer, Overlay and TrackSyncOffsets: they're read on every overlay tick
+	// and written from other goroutines (e.g. opacity fades) while debounced saves marshal them
+	mu sync.RWMutex
+
+	env map[string]envOverride // Settings overridden by environment variables, keyed by variable
 }
 
-// New creates a new config service
+// saveDebounceDelay is how long SaveDebounced waits, coalescing further changes into one write
+const saveDebounceDelay = 500 * time.Millisecond
+
+// DefaultProfile is the profile stored directly in ~/.spotly/config.json
+const DefaultProfile = "default"
+
+// profileNamePattern restricts profile names to safe directory names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// New creates a new config service for the default profile
 func New() (*Service, error) {
+	return NewForProfile(DefaultProfile)
+}
+
+// NewForProfile creates a config service for the named profile, creating it if needed
+func NewForProfile(name string) (*Service, error) {
+	path, err := ProfilePath(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithPath(path)
+}
+
+// baseDir returns the application data directory (~/.spotly)
+func baseDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
+	return filepath.Join(homeDir, ".spotly"), nil
+}
 
-	configDir := filepath.Join(homeDir, ".spotly")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create config directory: %w", err)
+// ProfilePath returns the config file path for the named profile
+func ProfilePath(name string) (string, error) {
+	dir, err := baseDir()
+	if err != nil {
+		return "", err
+	}
+	return profilePathIn(dir, name)
+}
+
+// profilePathIn returns the config file path for a profile under the given base directory
+func profilePathIn(dir, name string) (string, error) {
+	if name == DefaultProfile {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	if !profileNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name %q: use up to 32 letters, digits, '-' or '_'", name)
+	}
+	return filepath.Join(dir, "profiles", name, "config.json"), nil
+}
+
+// ListProfiles returns the default profile followed by any named profiles, sorted
+func ListProfiles() ([]string, error) {
+	dir, err := baseDir()
+	if err != nil {
+		return nil, err
+	}
+	return listProfilesIn(dir)
+}
+
+// listProfilesIn lists profiles stored under the given base directory
+func listProfilesIn(dir string) ([]string, error) {
+	profiles := []string{DefaultProfile}
+	entries, err := os.ReadDir(filepath.Join(dir, "profiles"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == DefaultProfile || !profileNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, "profiles", entry.Name(), "config.json")); err == nil {
+			profiles = append(profiles, entry.Name())
+		}
+	}
+	sort.Strings(profiles[1:])
+	return profiles, nil
+}
+
+// ProfileExists reports whether the named profile has a config file
+func ProfileExists(name string) bool {
+	path, err := ProfilePath(name)
+	if err != nil {
+		return false
 	}
+	_, err = os.Stat(path)
+	return err == nil
+}
 
-	configPath := filepath.Join(configDir, "config.json")
+// NewWithPath creates a config service backed by the given file path, with the Spotify
+// credentials overridden by SPOTLY_* environment variables when set (see EnvSpotifyClientID)
+func NewWithPath(configPath string) (*Service, error) {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
 
 	service := &Service{
 		filePath: configPath,
@@ -73,13 +322,18 @@ func New() (*Service, error) {
 	// Load existing config if it exists, otherwise create a default config file
 	if _, err := os.Stat(configPath); err == nil {
 		if err := service.Load(); err != nil {
-			return nil, fmt.Errorf("failed to load config: %w", err)
+			if !errors.Is(err, ErrConfigRecovered) {
+				return nil, fmt.Errorf("failed to load config: %w", err)
+			}
+			// Keep running on defaults so the user can re-enter credentials
+			log.Printf("Config: %v", err)
 		}
 	} else {
 		if err := service.Save(); err != nil {
 			return nil, fmt.Errorf("failed to create default config: %w", err)
 		}
 	}
+	service.applyEnv()
 
 	return service, nil
 }
@@ -90,18 +344,32 @@ func getDefaultConfig() *Config {
 		RedirectURI: "http://127.0.0.1:8080/callback",
 		Port:        8080,
 		Overlay: OverlayConfig{
-			X:            100,
-			Y:            100,
-			Width:        600,
-			Height:       120,
-			Opacity:      0.9,
-			FontSize:     16,
-			Visible:      true,
-			Locked:       false,
-			Position:     "bottom-left",
-			ResizeLocked: false,
-			SyncOffset:   350,
+			X:              100,
+			Y:              100,
+			Width:          600,
+			Height:         120,
+			Opacity:        0.9,
+			FontSize:       16,
+			Visible:        true,
+			Locked:         false,
+			Position:       "bottom-left",
+			ResizeLocked:   false,
+			SyncOffset:     350,
+			SmoothProgress: true,
+		},
+		HistorySize:             50,
+		CachePruneIntervalHours: 6,
+		ClickThroughBlocklist: []string{
+			"google chrome",
+			"mozilla firefox",
+			"microsoft edge",
+			"brave",
+		},
+		ProviderTimeouts: map[string]int64{
+			"LRCLIB": 8000,
 		},
+		LyricsLookupTimeout:  20000,
+		MaxConcurrentFetches: 2,
 	}
 }
 
@@ -122,17 +390,133 @@ func (s *Service) Load() error {
 		return err
 	}
 
-	return json.Unmarshal(data, s.config)
+	if err := json.Unmarshal(data, s.config); err != nil {
+		return s.recoverCorrupt(data, err)
+	}
+
+	// Hand-edited or older files may hold values that make the overlay unusable
+	if err := s.config.Overlay.Clamp(); err != nil {
+		log.Printf("Config: %v", err)
+	}
+	return nil
+}
+
+// recoverCorrupt backs up an unparseable config file and resets to defaults
+func (s *Service) recoverCorrupt(data []byte, parseErr error) error {
+	backupPath, err := writeBackup(s.filePath, data)
+	if err != nil {
+		return fmt.Errorf("failed to back up invalid config: %w", err)
+	}
+
+	s.config = getDefaultConfig()
+	if err := s.Save(); err != nil {
+		return fmt.Errorf("failed to reset invalid config: %w", err)
+	}
+
+	s.warning = fmt.Sprintf("config.json was invalid and has been reset to defaults (backup saved to %s)", backupPath)
+	return fmt.Errorf("%w: %v", ErrConfigRecovered, parseErr)
 }
 
-// Save saves configuration to file
+// writeBackup writes data to path.bak, or to path.bak.N for the first free N, so an earlier
+// backup is never overwritten. Returns the backup's path.
+func writeBackup(path string, data []byte) (string, error) {
+	for n := 0; ; n++ {
+		backupPath := path + ".bak"
+		if n > 0 {
+			backupPath = fmt.Sprintf("%s.bak.%d", path, n)
+		}
+		file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return backupPath, err
+	}
+}
+
+// Warning returns a non-fatal problem found while loading, or "" if none
+func (s *Service) Warning() string {
+	return s.warning
+}
+
+// Save saves configuration to file, replacing any pending debounced save
 func (s *Service) Save() error {
-	data, err := json.MarshalIndent(s.config, "", "  ")
+	s.saveMu.Lock()
+	if s.saveTimer != nil {
+		s.saveTimer.Stop()
+		s.saveTimer = nil
+	}
+	s.saveMu.Unlock()
+
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.withFileValues(s.config), "", "  ")
+	s.mu.RUnlock()
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(s.filePath, data, 0644)
+	return WriteFileAtomic(s.filePath, data)
+}
+
+// WriteFileAtomic writes data to a temp file beside path and renames it into place, so a crash
+// or a concurrent writer never leaves a half-written file (e.g. a config with lost OAuth tokens) behind
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// SaveDebounced schedules a save shortly, so bursts of changes (e.g. dragging a slider)
+// result in a single disk write
+func (s *Service) SaveDebounced() {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	if s.saveTimer != nil {
+		return // Already scheduled; it will pick up this change too
+	}
+	s.saveTimer = time.AfterFunc(saveDebounceDelay, func() {
+		if err := s.Flush(); err != nil {
+			log.Printf("Config: failed to save: %v", err)
+		}
+	})
+}
+
+// Flush writes a pending debounced save immediately, if there is one
+func (s *Service) Flush() error {
+	s.saveMu.Lock()
+	pending := s.saveTimer != nil
+	s.saveMu.Unlock()
+	if !pending {
+		return nil
+	}
+	return s.Save()
 }
 
 // Path returns the full path to the configuration file
@@ -141,13 +525,91 @@ func (s *Service) Path() string {
 }
 
 // UpdateOverlay updates overlay configuration
+// Out-of-range opacity and font size are clamped and saved, and reported as an error.
 func (s *Service) UpdateOverlay(overlay OverlayConfig) error {
+	clampErr := overlay.Clamp()
+	s.mu.Lock()
 	s.config.Overlay = overlay
+	s.mu.Unlock()
+	if err := s.Save(); err != nil {
+		return err
+	}
+	return clampErr
+}
+
+// Overlay returns a copy of the overlay settings, safe to read while they're being changed
+func (s *Service) Overlay() OverlayConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config.Overlay
+}
+
+// SetOverlay applies overlay configuration in memory right away and saves it debounced.
+// Like UpdateOverlay, out-of-range values are clamped and reported as an error.
+func (s *Service) SetOverlay(overlay OverlayConfig) error {
+	clampErr := overlay.Clamp()
+	s.mu.Lock()
+	s.config.Overlay = overlay
+	s.mu.Unlock()
+	s.SaveDebounced()
+	return clampErr
+}
+
+// TrackSyncOffset returns the saved sync offset for a track, if it has one
+func (s *Service) TrackSyncOffset(trackID string) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	offset, ok := s.config.TrackSyncOffsets[trackID]
+	return offset, ok
+}
+
+// SetTrackSyncOffset saves a sync offset for one track (debounced); 0 removes it so the
+// global offset applies again
+func (s *Service) SetTrackSyncOffset(trackID string, offsetMs int64) error {
+	if trackID == "" {
+		return fmt.Errorf("track ID is required")
+	}
+	s.mu.Lock()
+	if offsetMs == 0 {
+		delete(s.config.TrackSyncOffsets, trackID)
+	} else {
+		if s.config.TrackSyncOffsets == nil {
+			s.config.TrackSyncOffsets = make(map[string]int64)
+		}
+		s.config.TrackSyncOffsets[trackID] = offsetMs
+	}
+	s.mu.Unlock()
+	s.SaveDebounced()
+	return nil
+}
+
+// Allowed range for the local OAuth callback port (unprivileged ports only)
+const (
+	MinCallbackPort = 1024
+	MaxCallbackPort = 65535
+)
+
+// RedirectURIForPort returns the OAuth redirect URI served by the callback server on port
+func RedirectURIForPort(port int) string {
+	return fmt.Sprintf("http://127.0.0.1:%d/callback", port)
+}
+
+// SetCallbackPort validates port and updates Port and the derived RedirectURI, then saves
+func (s *Service) SetCallbackPort(port int) error {
+	if port < MinCallbackPort || port > MaxCallbackPort {
+		return fmt.Errorf("port %d is out of range (%d-%d)", port, MinCallbackPort, MaxCallbackPort)
+	}
+	s.mu.Lock()
+	s.config.Port = port
+	s.config.RedirectURI = RedirectURIForPort(port)
+	s.mu.Unlock()
 	return s.Save()
 }
 
 // UpdateAuth updates auth configuration
 func (s *Service) UpdateAuth(auth AuthConfig) error {
+	s.mu.Lock()
 	s.config.Auth = auth
+	s.mu.Unlock()
 	return s.Save()
 }
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_Default(t *testing.T) {
@@ -195,3 +200,530 @@ func TestGetDefaultConfig(t *testing.T) {
 		t.Errorf("Expected default font size 16, got %d", cfg.Overlay.FontSize)
 	}
 }
+
+func TestNewWithPath_CorruptConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	malformed := []byte(`{"spotify_client_id": "abc", "overlay": {`)
+	if err := os.WriteFile(configPath, malformed, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	service, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath should recover from a corrupt file, got: %v", err)
+	}
+
+	if service.Warning() == "" {
+		t.Error("Expected a recovery warning")
+	}
+
+	cfg := service.Get()
+	if cfg.Port != 8080 || cfg.SpotifyClientID != "" {
+		t.Errorf("Expected default config after recovery, got port %d id %q", cfg.Port, cfg.SpotifyClientID)
+	}
+
+	backup, err := os.ReadFile(configPath + ".bak")
+	if err != nil {
+		t.Fatalf("Expected backup file: %v", err)
+	}
+	if string(backup) != string(malformed) {
+		t.Errorf("Backup content = %q; want original file", backup)
+	}
+
+	// The rewritten file should now load cleanly
+	reloaded, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if reloaded.Warning() != "" {
+		t.Errorf("Expected no warning after reset, got %q", reloaded.Warning())
+	}
+}
+
+func TestLoad_CorruptConfigReturnsRecoverable(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	if err := os.WriteFile(configPath, []byte("not json"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	service := &Service{
+		filePath: configPath,
+		config:   getDefaultConfig(),
+	}
+
+	err := service.Load()
+	if !errors.Is(err, ErrConfigRecovered) {
+		t.Errorf("Load error = %v; want ErrConfigRecovered", err)
+	}
+}
+
+func TestLoad_CorruptConfigKeepsEarlierBackup(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath+".bak", []byte(`{"port": 9000}`), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	// Two corruptions in a row each get their own backup
+	for _, corrupt := range []string{"first", "second"} {
+		if err := os.WriteFile(configPath, []byte(corrupt), 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		if _, err := NewWithPath(configPath); err != nil {
+			t.Fatalf("NewWithPath failed: %v", err)
+		}
+	}
+
+	for path, want := range map[string]string{
+		configPath + ".bak":   `{"port": 9000}`,
+		configPath + ".bak.1": "first",
+		configPath + ".bak.2": "second",
+	} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Expected backup %s: %v", filepath.Base(path), err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q; want %q", filepath.Base(path), got, want)
+		}
+	}
+}
+
+func TestConfig_UpdateOverlayClampsAppearance(t *testing.T) {
+	tests := []struct {
+		name         string
+		opacity      float64
+		fontSize     int
+		wantOpacity  float64
+		wantFontSize int
+		wantErr      bool
+	}{
+		{"in range", 0.5, 20, 0.5, 20, false},
+		{"opacity too high", 5.0, 20, MaxOpacity, 20, true},
+		{"opacity too low", 0, 20, MinOpacity, 20, true},
+		{"negative font size", 0.5, -3, 0.5, MinFontSize, true},
+		{"huge font size", 0.5, 9999, 0.5, MaxFontSize, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &Service{
+				filePath: filepath.Join(t.TempDir(), "config.json"),
+				config:   getDefaultConfig(),
+			}
+
+			err := service.UpdateOverlay(OverlayConfig{Opacity: tc.opacity, FontSize: tc.fontSize})
+			if (err != nil) != tc.wantErr {
+				t.Errorf("UpdateOverlay error = %v; wantErr %v", err, tc.wantErr)
+			}
+			got := service.Get().Overlay
+			if got.Opacity != tc.wantOpacity || got.FontSize != tc.wantFontSize {
+				t.Errorf("Overlay = opacity %v, font %d; want %v, %d", got.Opacity, got.FontSize, tc.wantOpacity, tc.wantFontSize)
+			}
+		})
+	}
+}
+
+func TestLoad_ClampsOverlayAppearance(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"overlay": {"opacity": 3, "font_size": 500}}`), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	service, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath failed: %v", err)
+	}
+	if got := service.Get().Overlay; got.Opacity != MaxOpacity || got.FontSize != MaxFontSize {
+		t.Errorf("Loaded overlay = opacity %v, font %d; want clamped", got.Opacity, got.FontSize)
+	}
+}
+
+func TestProfilePathIn(t *testing.T) {
+	dir := t.TempDir()
+
+	if got, _ := profilePathIn(dir, DefaultProfile); got != filepath.Join(dir, "config.json") {
+		t.Errorf("Default profile path = %q; want base config.json", got)
+	}
+	if got, _ := profilePathIn(dir, "stream"); got != filepath.Join(dir, "profiles", "stream", "config.json") {
+		t.Errorf("Named profile path = %q", got)
+	}
+	for _, bad := range []string{"", "../escape", "a/b", "has space"} {
+		if _, err := profilePathIn(dir, bad); err == nil {
+			t.Errorf("Expected error for profile name %q", bad)
+		}
+	}
+}
+
+func TestListProfilesIn(t *testing.T) {
+	dir := t.TempDir()
+
+	profiles, err := listProfilesIn(dir)
+	if err != nil || len(profiles) != 1 || profiles[0] != DefaultProfile {
+		t.Fatalf("listProfilesIn (empty) = %v, %v; want [default]", profiles, err)
+	}
+
+	for _, name := range []string{"work", "stream"} {
+		path, _ := profilePathIn(dir, name)
+		if _, err := NewWithPath(path); err != nil {
+			t.Fatalf("NewWithPath(%s) failed: %v", name, err)
+		}
+	}
+	// A directory without a config file isn't a profile
+	if err := os.MkdirAll(filepath.Join(dir, "profiles", "partial"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err = listProfilesIn(dir)
+	if err != nil {
+		t.Fatalf("listProfilesIn failed: %v", err)
+	}
+	want := []string{DefaultProfile, "stream", "work"}
+	if len(profiles) != len(want) {
+		t.Fatalf("Profiles = %v; want %v", profiles, want)
+	}
+	for i := range want {
+		if profiles[i] != want[i] {
+			t.Errorf("Profiles = %v; want %v", profiles, want)
+			break
+		}
+	}
+}
+
+func TestConfig_SetOverlayDebouncesSave(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	service := &Service{
+		filePath: configPath,
+		config:   getDefaultConfig(),
+	}
+
+	// A burst of slider updates applies in memory without touching the disk
+	for opacity := 0.5; opacity <= 0.8; opacity += 0.1 {
+		if err := service.SetOverlay(OverlayConfig{Opacity: opacity, FontSize: 16}); err != nil {
+			t.Fatalf("SetOverlay failed: %v", err)
+		}
+	}
+	if service.Get().Overlay.Opacity < 0.79 {
+		t.Errorf("Expected opacity to apply immediately, got %v", service.Get().Overlay.Opacity)
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Fatalf("Expected no write before the debounce delay, stat err = %v", err)
+	}
+
+	// Committing writes the latest values right away
+	if err := service.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Expected config to be written on Flush: %v", err)
+	}
+	var saved Config
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if saved.Overlay.Opacity < 0.79 {
+		t.Errorf("Saved opacity = %v; want the latest value", saved.Overlay.Opacity)
+	}
+}
+
+func TestConfig_SaveDebouncedWritesAfterDelay(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	service := &Service{
+		filePath: configPath,
+		config:   getDefaultConfig(),
+	}
+
+	service.SaveDebounced()
+	deadline := time.Now().Add(5 * saveDebounceDelay)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(configPath); err == nil {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Error("Expected the debounced save to write the config")
+}
+
+func TestConfig_SaveIsAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	service := &Service{
+		filePath: configPath,
+		config:   getDefaultConfig(),
+	}
+
+	for i := 0; i < 3; i++ {
+		service.Get().Overlay.X = i
+		if err := service.Save(); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "config.json" {
+		t.Errorf("Config dir holds %v; want only config.json (no leftover temp files)", entries)
+	}
+
+	loaded := &Service{filePath: configPath, config: getDefaultConfig()}
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Get().Overlay.X != 2 {
+		t.Errorf("Loaded X = %d; want 2", loaded.Get().Overlay.X)
+	}
+}
+
+func TestConfig_SetCallbackPort(t *testing.T) {
+	service := &Service{
+		filePath: filepath.Join(t.TempDir(), "config.json"),
+		config:   getDefaultConfig(),
+	}
+
+	if err := service.SetCallbackPort(9090); err != nil {
+		t.Fatalf("SetCallbackPort failed: %v", err)
+	}
+	if cfg := service.Get(); cfg.Port != 9090 || cfg.RedirectURI != "http://127.0.0.1:9090/callback" {
+		t.Errorf("Port/RedirectURI = %d/%q; want 9090 and matching URI", cfg.Port, cfg.RedirectURI)
+	}
+
+	for _, port := range []int{0, 80, 70000} {
+		if err := service.SetCallbackPort(port); err == nil {
+			t.Errorf("SetCallbackPort(%d) should fail", port)
+		}
+	}
+	if service.Get().Port != 9090 {
+		t.Errorf("Invalid ports should leave the config unchanged, got %d", service.Get().Port)
+	}
+}
+
+func TestConfig_TrackSyncOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	service := &Service{filePath: path, config: getDefaultConfig()}
+
+	if _, ok := service.TrackSyncOffset("track1"); ok {
+		t.Error("Expected no offset before one is set")
+	}
+	if err := service.SetTrackSyncOffset("track1", -250); err != nil {
+		t.Fatalf("SetTrackSyncOffset failed: %v", err)
+	}
+	if err := service.SetTrackSyncOffset("", 100); err == nil {
+		t.Error("SetTrackSyncOffset without a track ID should fail")
+	}
+	if err := service.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	loaded, err := NewWithPath(path)
+	if err != nil {
+		t.Fatalf("NewWithPath failed: %v", err)
+	}
+	if offset, ok := loaded.TrackSyncOffset("track1"); !ok || offset != -250 {
+		t.Errorf("Loaded offset = %d, %v; want -250", offset, ok)
+	}
+
+	if err := service.SetTrackSyncOffset("track1", 0); err != nil {
+		t.Fatalf("SetTrackSyncOffset(0) failed: %v", err)
+	}
+	if _, ok := service.TrackSyncOffset("track1"); ok {
+		t.Error("Expected a zero offset to remove the override")
+	}
+}
+
+func TestConfig_ExportImport(t *testing.T) {
+	dir := t.TempDir()
+	source := &Service{filePath: filepath.Join(dir, "source.json"), config: getDefaultConfig()}
+	source.Get().SpotifyClientID = "client"
+	source.Get().SpotifyClientSecret = "source-secret"
+	source.Get().Auth.AccessToken = "source-token"
+	source.Get().Overlay.FontSize = 24
+	source.Get().HistorySize = 10
+
+	exportPath := filepath.Join(dir, "export.json")
+	if err := source.Export(exportPath, false); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if strings.Contains(string(data), "source-secret") || strings.Contains(string(data), "source-token") {
+		t.Errorf("Export without secrets leaked them: %s", data)
+	}
+
+	// The destination already signed in with the same client keeps its own tokens
+	dest := &Service{filePath: filepath.Join(dir, "dest.json"), config: getDefaultConfig()}
+	dest.Get().SpotifyClientID = "client"
+	dest.Get().SpotifyClientSecret = "dest-secret"
+	dest.Get().Auth.AccessToken = "dest-token"
+	held := dest.Get()
+	if err := dest.Import(exportPath); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	cfg := dest.Get()
+	if cfg != held {
+		t.Error("Import replaced the config pointer; callers holding the old one would miss the settings")
+	}
+	if cfg.Overlay.FontSize != 24 || cfg.HistorySize != 10 {
+		t.Errorf("Imported font size/history = %d/%d; want 24/10", cfg.Overlay.FontSize, cfg.HistorySize)
+	}
+	if cfg.SpotifyClientSecret != "dest-secret" || cfg.Auth.AccessToken != "dest-token" {
+		t.Errorf("Import dropped local credentials: secret %q, token %q", cfg.SpotifyClientSecret, cfg.Auth.AccessToken)
+	}
+
+	// Invalid files leave the config alone
+	badPath := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(badPath, []byte(`{"port": 80, "history_size": 5}`), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := dest.Import(badPath); err == nil {
+		t.Error("Expected an out-of-range port to be rejected")
+	}
+	if dest.Get().HistorySize != 10 {
+		t.Errorf("Rejected import changed history size to %d", dest.Get().HistorySize)
+	}
+}
+
+func TestOverlayConfig_ClampBackground(t *testing.T) {
+	tests := []struct {
+		name        string
+		color       string
+		opacity     float64
+		wantColor   string
+		wantOpacity float64
+		wantErr     bool
+	}{
+		{"transparent default", "", 0, "", 0, false},
+		{"valid color", "#1A1A1A", 0.6, "#1A1A1A", 0.6, false},
+		{"short color", "#000", 1, "#000", 1, false},
+		{"named color rejected", "black", 0.5, "", 0.5, true},
+		{"opacity too high", "#000000", 2, "#000000", 1, true},
+		{"negative opacity", "#000000", -1, "#000000", 0, true},
+	}
+
+	for _, tc := range tests {
+		overlay := OverlayConfig{Opacity: 0.9, FontSize: 16, BackgroundColor: tc.color, BackgroundOpacity: tc.opacity}
+		err := overlay.Clamp()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: Clamp error = %v; wantErr %v", tc.name, err, tc.wantErr)
+		}
+		if overlay.BackgroundColor != tc.wantColor || overlay.BackgroundOpacity != tc.wantOpacity {
+			t.Errorf("%s: background = %q/%v; want %q/%v", tc.name, overlay.BackgroundColor, overlay.BackgroundOpacity, tc.wantColor, tc.wantOpacity)
+		}
+	}
+}
+
+func TestConfig_EnvOverridesCredentials(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	data, _ := json.Marshal(&Config{SpotifyClientID: "file-id", SpotifyClientSecret: "file-secret", Port: 8080})
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv(EnvSpotifyClientID, "env-id")
+	t.Setenv(EnvSpotifyClientSecret, "env-secret")
+
+	service, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath failed: %v", err)
+	}
+	cfg := service.Get()
+	if cfg.SpotifyClientID != "env-id" || cfg.SpotifyClientSecret != "env-secret" {
+		t.Errorf("Effective credentials = %q / %q; want the environment's", cfg.SpotifyClientID, cfg.SpotifyClientSecret)
+	}
+
+	// Saving keeps the file's credentials, but not the environment's
+	cfg.Port = 9000
+	if err := service.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	var saved Config
+	data, _ = os.ReadFile(configPath)
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to parse saved config: %v", err)
+	}
+	if saved.SpotifyClientID != "file-id" || saved.SpotifyClientSecret != "file-secret" || saved.Port != 9000 {
+		t.Errorf("Saved config = %q / %q port %d; want file credentials and port 9000", saved.SpotifyClientID, saved.SpotifyClientSecret, saved.Port)
+	}
+	if cfg.SpotifyClientID != "env-id" {
+		t.Errorf("SpotifyClientID after save = %q; want the environment's still in effect", cfg.SpotifyClientID)
+	}
+
+	// Unset variables leave the file's values in effect
+	t.Setenv(EnvSpotifyClientID, "")
+	t.Setenv(EnvSpotifyClientSecret, "")
+	reloaded, err := NewWithPath(configPath)
+	if err != nil {
+		t.Fatalf("NewWithPath failed: %v", err)
+	}
+	if reloaded.Get().SpotifyClientID != "file-id" {
+		t.Errorf("SpotifyClientID without env = %q; want %q", reloaded.Get().SpotifyClientID, "file-id")
+	}
+}
+
+func TestConfig_SetOverlayWhileSaving(t *testing.T) {
+	service, err := NewWithPath(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("NewWithPath failed: %v", err)
+	}
+
+	// An opacity fade writes the overlay settings while saves marshal them (go test -race)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			overlay := service.Overlay()
+			overlay.Opacity = 0.5 + float64(i)/100
+			_ = service.SetOverlay(overlay)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		if err := service.Save(); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	<-done
+	if err := service.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if got := service.Overlay().Opacity; got != 0.99 {
+		t.Errorf("Opacity = %v; want the last fade step 0.99", got)
+	}
+}
+
+func TestConfig_SetCallbackPortWhileSaving(t *testing.T) {
+	service, err := NewWithPath(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("NewWithPath failed: %v", err)
+	}
+
+	// The token refresher and the port setting write while saves marshal (go test -race)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			_ = service.SetCallbackPort(9000 + i)
+			_ = service.UpdateAuth(AuthConfig{AccessToken: "token-" + strconv.Itoa(i)})
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		if err := service.Save(); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	<-done
+	if err := service.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	if cfg := service.Get(); cfg.Port != 9019 || cfg.RedirectURI != RedirectURIForPort(9019) {
+		t.Errorf("Port/RedirectURI = %d/%q; want the last update 9019", cfg.Port, cfg.RedirectURI)
+	}
+}
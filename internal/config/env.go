@@ -0,0 +1,57 @@
+package config
+
+import "os"
+
+// Environment variables overriding the Spotify credentials, for CI, development and portable
+// setups. Precedence is environment > config file > default; overridden values are never saved.
+const (
+	EnvSpotifyClientID     = "SPOTLY_SPOTIFY_CLIENT_ID"
+	EnvSpotifyClientSecret = "SPOTLY_SPOTIFY_CLIENT_SECRET"
+)
+
+// envFields ties each environment variable to the setting it overrides
+var envFields = []struct {
+	name  string
+	field func(*Config) *string
+}{
+	{EnvSpotifyClientID, func(c *Config) *string { return &c.SpotifyClientID }},
+	{EnvSpotifyClientSecret, func(c *Config) *string { return &c.SpotifyClientSecret }},
+}
+
+// envOverride remembers the file value an environment variable replaced, so saves keep it
+type envOverride struct {
+	value     string
+	fileValue string
+}
+
+// applyEnv overlays set environment variables on the loaded config
+func (s *Service) applyEnv() {
+	for _, env := range envFields {
+		value := os.Getenv(env.name)
+		if value == "" {
+			continue
+		}
+		field := env.field(s.config)
+		if s.env == nil {
+			s.env = make(map[string]envOverride)
+		}
+		s.env[env.name] = envOverride{value: value, fileValue: *field}
+		*field = value
+	}
+}
+
+// withFileValues returns cfg with environment overrides swapped back for the file's values, so
+// they don't end up on disk. Settings changed since they were overridden are kept.
+func (s *Service) withFileValues(cfg *Config) *Config {
+	if len(s.env) == 0 {
+		return cfg
+	}
+	persisted := *cfg
+	for _, env := range envFields {
+		override, ok := s.env[env.name]
+		if field := env.field(&persisted); ok && *field == override.value {
+			*field = override.fileValue
+		}
+	}
+	return &persisted
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Export writes the configuration to path for moving to another machine. Unless includeSecrets
+// is set, the OAuth tokens and Spotify client secret are left out.
+func (s *Service) Export(path string, includeSecrets bool) error {
+	var exported *Config
+	var err error
+	if includeSecrets {
+		exported, err = s.clone()
+	} else {
+		exported, err = s.Redacted()
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s.withFileValues(exported), "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, data)
+}
+
+// Import merges an exported configuration from path into the current one and saves it. Settings
+// missing from the file keep their current values. The file is validated first; on error the
+// current configuration is left untouched.
+func (s *Service) Import(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var incoming Config
+	if err := json.Unmarshal(data, &incoming); err != nil {
+		return fmt.Errorf("invalid config file: %w", err)
+	}
+
+	merged, err := s.clone()
+	if err != nil {
+		return err
+	}
+	previousID, previousSecret, previousAuth := merged.SpotifyClientID, merged.SpotifyClientSecret, merged.Auth
+	if err := json.Unmarshal(data, merged); err != nil {
+		return fmt.Errorf("invalid config file: %w", err)
+	}
+
+	// An export without secrets keeps this machine's, as long as they belong to the same client
+	sameClient := merged.SpotifyClientID == previousID
+	if incoming.SpotifyClientSecret == "" && sameClient {
+		merged.SpotifyClientSecret = previousSecret
+	}
+	if incoming.Auth.AccessToken == "" {
+		merged.Auth = AuthConfig{}
+		if sameClient {
+			merged.Auth = previousAuth
+		}
+	}
+	if err := validateImported(merged); err != nil {
+		return err
+	}
+
+	// Copy in place: callers keep the pointer from Get, and must see the imported settings
+	s.mu.Lock()
+	*s.config = *merged
+	s.mu.Unlock()
+	return s.Save()
+}
+
+// validateImported rejects settings that would break the app and clamps overlay values
+func validateImported(cfg *Config) error {
+	if cfg.Port < MinCallbackPort || cfg.Port > MaxCallbackPort {
+		return fmt.Errorf("invalid config file: port %d is out of range (%d-%d)", cfg.Port, MinCallbackPort, MaxCallbackPort)
+	}
+	if cfg.RedirectURI == "" {
+		cfg.RedirectURI = RedirectURIForPort(cfg.Port)
+	}
+	if cfg.HistorySize < 0 || cfg.MaxConcurrentFetches < 0 || cfg.LyricsLookupTimeout < 0 {
+		return fmt.Errorf("invalid config file: negative sizes or timeouts")
+	}
+	if err := cfg.Overlay.Clamp(); err != nil {
+		// Out-of-range appearance values are recoverable
+		log.Printf("Config: import: %v", err)
+	}
+	return nil
+}
+
+// Redacted returns a copy of the configuration without the OAuth tokens and client secret
+func (s *Service) Redacted() (*Config, error) {
+	redacted, err := s.clone()
+	if err != nil {
+		return nil, err
+	}
+	redacted.SpotifyClientSecret = ""
+	redacted.Auth = AuthConfig{}
+	return redacted, nil
+}
+
+// clone returns a deep copy of the current configuration
+func (s *Service) clone() (*Config, error) {
+	s.mu.RLock()
+	data, err := json.Marshal(s.config)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	copied := &Config{}
+	if err := json.Unmarshal(data, copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
@@ -0,0 +1,117 @@
+package history
+
+import (
+	"sync"
+	"time"
+
+	"lyrics-overlay/internal/overlay"
+)
+
+// Service keeps a bounded list of recently played tracks
+type Service struct {
+	mu      sync.RWMutex
+	maxSize int
+	entries []Entry // Most recent first
+}
+
+// Entry holds metadata about a previously played track
+type Entry struct {
+	TrackID     string    `json:"track_id"`
+	Name        string    `json:"name"`
+	Artists     []string  `json:"artists"`
+	Album       string    `json:"album"`
+	Duration    int64     `json:"duration_ms"`
+	StartedAt   time.Time `json:"started_at"`
+	LyricsFound bool      `json:"lyrics_found"`
+}
+
+// New creates a new history service
+func New(maxSize int) *Service {
+	if maxSize <= 0 {
+		maxSize = 50 // Default history size
+	}
+
+	return &Service{
+		maxSize: maxSize,
+		entries: make([]Entry, 0, maxSize),
+	}
+}
+
+// Record adds a track to the front of the history as it starts playing
+func (s *Service) Record(track *overlay.TrackInfo) {
+	if track == nil || track.ID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Keep each track once, moving replays to the front
+	lyricsFound := false
+	for i, entry := range s.entries {
+		if entry.TrackID == track.ID {
+			lyricsFound = entry.LyricsFound
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			break
+		}
+	}
+
+	entry := Entry{
+		TrackID:     track.ID,
+		Name:        track.Name,
+		Artists:     append([]string(nil), track.Artists...),
+		Album:       track.Album,
+		Duration:    track.Duration,
+		StartedAt:   time.Now(),
+		LyricsFound: lyricsFound,
+	}
+	s.entries = append([]Entry{entry}, s.entries...)
+
+	// Enforce size limit
+	if len(s.entries) > s.maxSize {
+		s.entries = s.entries[:s.maxSize]
+	}
+}
+
+// SetLyricsFound records whether lyrics were found for a track in the history
+func (s *Service) SetLyricsFound(trackID string, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.entries {
+		if s.entries[i].TrackID == trackID {
+			s.entries[i].LyricsFound = found
+			return
+		}
+	}
+}
+
+// Get returns the history entry for a track, if present
+func (s *Service) Get(trackID string) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, entry := range s.entries {
+		if entry.TrackID == trackID {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Entries returns a snapshot of the history, most recent first
+func (s *Service) Entries() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]Entry, len(s.entries))
+	copy(entries, s.entries)
+	return entries
+}
+
+// Clear removes all history entries
+func (s *Service) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make([]Entry, 0, s.maxSize)
+}
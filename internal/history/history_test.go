@@ -0,0 +1,68 @@
+package history
+
+import (
+	"testing"
+
+	"lyrics-overlay/internal/overlay"
+)
+
+func TestService_RecordOrder(t *testing.T) {
+	h := New(5)
+
+	h.Record(&overlay.TrackInfo{ID: "a", Name: "A"})
+	h.Record(&overlay.TrackInfo{ID: "b", Name: "B"})
+
+	entries := h.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].TrackID != "b" || entries[1].TrackID != "a" {
+		t.Errorf("Unexpected order: %s, %s", entries[0].TrackID, entries[1].TrackID)
+	}
+}
+
+func TestService_RecordUnique(t *testing.T) {
+	h := New(5)
+
+	h.Record(&overlay.TrackInfo{ID: "a"})
+	h.SetLyricsFound("a", true)
+	h.Record(&overlay.TrackInfo{ID: "b"})
+	h.Record(&overlay.TrackInfo{ID: "a"}) // Replay moves to front
+
+	entries := h.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].TrackID != "a" {
+		t.Errorf("Expected replayed track at front, got %s", entries[0].TrackID)
+	}
+	if !entries[0].LyricsFound {
+		t.Error("Expected lyrics found flag to survive a replay")
+	}
+}
+
+func TestService_Bounded(t *testing.T) {
+	h := New(2)
+
+	h.Record(&overlay.TrackInfo{ID: "a"})
+	h.Record(&overlay.TrackInfo{ID: "b"})
+	h.Record(&overlay.TrackInfo{ID: "c"}) // Should drop "a"
+
+	if _, ok := h.Get("a"); ok {
+		t.Error("Expected 'a' to be dropped")
+	}
+	if len(h.Entries()) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(h.Entries()))
+	}
+}
+
+func TestService_IgnoresEmptyTrack(t *testing.T) {
+	h := New(2)
+
+	h.Record(nil)
+	h.Record(&overlay.TrackInfo{})
+
+	if len(h.Entries()) != 0 {
+		t.Errorf("Expected no entries, got %d", len(h.Entries()))
+	}
+}
@@ -0,0 +1,59 @@
+package lyrics
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// missTTL is how long a track no provider had lyrics for is reported as unavailable
+	missTTL = 30 * time.Minute
+	// maxMisses bounds the negative cache; the oldest entries are dropped first
+	maxMisses = 1000
+)
+
+// recordMiss remembers that no provider had lyrics for the normalized key
+func (s *Service) recordMiss(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.misses[key] = now
+	if len(s.misses) <= maxMisses {
+		return
+	}
+	oldestKey, oldest := "", now
+	for k, at := range s.misses {
+		if at.Before(oldest) {
+			oldestKey, oldest = k, at
+		}
+	}
+	delete(s.misses, oldestKey)
+}
+
+// recentMiss reports whether the normalized key was a miss within missTTL
+func (s *Service) recentMiss(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	at, ok := s.misses[key]
+	return ok && s.now().Sub(at) < missTTL
+}
+
+// HasLyrics reports whether lyrics are available for a track without displaying them. Cached
+// lyrics and recent misses answer immediately; otherwise, if probe is set, the providers are
+// queried (filling the cache for later playback).
+func (s *Service) HasLyrics(ctx context.Context, trackID, artist, title string, probe bool) bool {
+	artist = cleanArtist(artist)
+	if cached := s.cache.GetByTrackID(trackID); cached != nil && !isPlaceholderSource(cached.Source) {
+		return cached.HasLyrics()
+	}
+	key := normalizeForCache(artist, title)
+	if cached := s.cache.GetByKey(key); cached != nil && !isPlaceholderSource(cached.Source) {
+		return cached.HasLyrics()
+	}
+	if s.recentMiss(key) || !probe {
+		return false
+	}
+
+	lyrics, err := s.lookup(ctx, TrackQuery{TrackID: trackID, Artist: artist, Title: title})
+	return err == nil && lyrics != nil && !isPlaceholderSource(lyrics.Source) && lyrics.HasLyrics()
+}
@@ -0,0 +1,120 @@
+package lyrics
+
+import (
+	"context"
+	"fmt"
+
+	"lyrics-overlay/internal/overlay"
+)
+
+// LyricsCandidate is an LRCLIB search result offered for manual selection
+type LyricsCandidate struct {
+	ID           int    `json:"id"`
+	Artist       string `json:"artist"`
+	Title        string `json:"title"`
+	Album        string `json:"album"`
+	DurationMs   int64  `json:"duration_ms"`
+	Synced       bool   `json:"synced"`
+	Instrumental bool   `json:"instrumental"`
+}
+
+// Candidates lists LRCLIB search results for the track, using the same loosened query fallback
+// as SearchTrack when the structured search finds nothing
+func (l *LRCLibProvider) Candidates(ctx context.Context, artist, title string) ([]LyricsCandidate, error) {
+	results, err := l.search(ctx, artist, title)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		for _, q := range loosenedQueries(artist, title) {
+			if results, err = l.searchByQuery(ctx, q); err != nil {
+				return nil, err
+			}
+			if len(results) > 0 {
+				break
+			}
+		}
+	}
+
+	candidates := make([]LyricsCandidate, 0, len(results))
+	for _, r := range results {
+		candidates = append(candidates, LyricsCandidate{
+			ID:           r.ID,
+			Artist:       r.ArtistName,
+			Title:        r.TrackName,
+			Album:        r.AlbumName,
+			DurationMs:   int64(r.Duration * 1000),
+			Synced:       r.SyncedLyrics != "",
+			Instrumental: r.Instrumental,
+		})
+	}
+	return candidates, nil
+}
+
+// LyricsByID fetches the lyrics of one LRCLIB record
+func (l *LRCLibProvider) LyricsByID(ctx context.Context, id int) (*overlay.LyricsData, error) {
+	track, err := l.getByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("lrclib: %w", err)
+	}
+	data := l.trackToLyricsData(track, false)
+	if data == nil {
+		return nil, fmt.Errorf("lrclib record %d has no lyrics: %w", id, ErrNoLyrics)
+	}
+	return data, nil
+}
+
+// lrclib returns the registered LRCLIB provider, if any
+func (s *Service) lrclib() *LRCLibProvider {
+	for _, provider := range s.providers {
+		if l, ok := provider.(*LRCLibProvider); ok {
+			return l
+		}
+	}
+	return nil
+}
+
+// SetPreferPlainOnLowConfidence makes LRCLIB return plain lyrics instead of synced ones for
+// fuzzy or duration-mismatched matches (see LRCLibProvider.SetPreferPlainOnLowConfidence)
+func (s *Service) SetPreferPlainOnLowConfidence(enabled bool) {
+	if l := s.lrclib(); l != nil {
+		l.SetPreferPlainOnLowConfidence(enabled)
+	}
+}
+
+// ListCandidates returns the LRCLIB matches for a track so the user can pick one
+func (s *Service) ListCandidates(ctx context.Context, artist, title string) ([]LyricsCandidate, error) {
+	l := s.lrclib()
+	if l == nil {
+		return nil, fmt.Errorf("LRCLIB provider not available")
+	}
+	return l.Candidates(ctx, cleanArtist(artist), title)
+}
+
+// SelectCandidate fetches the chosen LRCLIB record and caches it for the track in place of
+// whatever was matched automatically
+func (s *Service) SelectCandidate(ctx context.Context, track *overlay.TrackInfo, id int) (*overlay.LyricsData, error) {
+	l := s.lrclib()
+	if l == nil {
+		return nil, fmt.Errorf("LRCLIB provider not available")
+	}
+	lyrics, err := l.LyricsByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	artist := ""
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0]
+	}
+	lyrics.TrackID = track.ID
+	lyrics.MatchKind = overlay.MatchExact // Chosen by the user
+	lyrics.MatchConfidence = 1
+
+	s.cacheByTrackID(track.ID, lyrics)
+	s.cache.SetByKey(normalizeForCache(cleanArtist(artist), track.Name), lyrics)
+	if isrcKey := isrcCacheKey(track.ISRC); isrcKey != "" {
+		s.cache.SetByKey(isrcKey, lyrics)
+	}
+	return lyrics, nil
+}
@@ -0,0 +1,54 @@
+package lyrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lyrics-overlay/internal/cache"
+	"lyrics-overlay/internal/overlay"
+)
+
+func TestCandidatesAndSelect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search":
+			fmt.Fprint(w, `[
+				{"id": 1, "trackName": "Song", "artistName": "Artist", "albumName": "Live", "duration": 240, "plainLyrics": "Live words"},
+				{"id": 2, "trackName": "Song", "artistName": "Artist", "albumName": "Studio", "duration": 200, "syncedLyrics": "[00:01.00]Studio words"}
+			]`)
+		case "/get/2":
+			fmt.Fprint(w, `{"id": 2, "trackName": "Song", "artistName": "Artist", "duration": 200, "syncedLyrics": "[00:01.00]Studio words"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewLRCLibProvider(server.Client(), server.URL)
+	c := cache.New(10)
+	s := NewWithProviders(c, provider)
+
+	candidates, err := s.ListCandidates(context.Background(), "Artist", "Song")
+	if err != nil {
+		t.Fatalf("ListCandidates failed: %v", err)
+	}
+	if len(candidates) != 2 || candidates[1].ID != 2 || !candidates[1].Synced || candidates[0].DurationMs != 240000 {
+		t.Fatalf("Candidates = %+v; want both results with ID, synced flag and duration", candidates)
+	}
+
+	track := &overlay.TrackInfo{ID: "track1", Name: "Song", Artists: []string{"Artist"}}
+	lyrics, err := s.SelectCandidate(context.Background(), track, 2)
+	if err != nil {
+		t.Fatalf("SelectCandidate failed: %v", err)
+	}
+	if len(lyrics.Lines) == 0 || lyrics.Lines[0].Text != "Studio words" {
+		t.Errorf("Selected lyrics = %+v; want the studio record", lyrics.Lines)
+	}
+	if cached := c.GetByTrackID("track1"); cached == nil || cached.Lines[0].Text != "Studio words" {
+		t.Errorf("Expected the selection to be cached for the track, got %+v", cached)
+	}
+}
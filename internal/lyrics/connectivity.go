@@ -0,0 +1,87 @@
+package lyrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultCheckTimeout bounds each connectivity probe
+const DefaultCheckTimeout = 5 * time.Second
+
+// ProviderCheck is the result of probing one service's API
+type ProviderCheck struct {
+	Name      string `json:"name"`
+	Reachable bool   `json:"reachable"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Pinger is implemented by providers that can check their API is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks that the LRCLIB API answers at its base URL
+func (l *LRCLibProvider) Ping(ctx context.Context) error {
+	return PingURL(ctx, l.client, l.baseURL)
+}
+
+// PingURL sends a GET to endpoint; any HTTP response counts as reachable, since only network
+// failures (DNS, TLS, timeouts) are of interest
+func PingURL(ctx context.Context, client *http.Client, endpoint string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := newProviderRequest(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("server error: %s", resp.Status)
+	}
+	return nil
+}
+
+// Check runs ping with timeout and reports the outcome under name
+func Check(ctx context.Context, name string, timeout time.Duration, ping func(context.Context) error) ProviderCheck {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ping(ctx)
+	result := ProviderCheck{Name: name, Reachable: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// CheckConnectivity probes each enabled provider that supports it. Meant for on-demand
+// diagnostics, not the lookup path.
+func (s *Service) CheckConnectivity(ctx context.Context, timeout time.Duration) []ProviderCheck {
+	s.mu.RLock()
+	var names []string
+	var pingers []Pinger
+	for _, provider := range s.providers {
+		if pinger, ok := provider.(Pinger); ok && !s.disabled[provider.GetName()] {
+			names = append(names, provider.GetName())
+			pingers = append(pingers, pinger)
+		}
+	}
+	s.mu.RUnlock()
+
+	checks := make([]ProviderCheck, len(pingers))
+	for i, pinger := range pingers {
+		checks[i] = Check(ctx, names[i], timeout, pinger.Ping)
+	}
+	return checks
+}
@@ -0,0 +1,48 @@
+package lyrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"lyrics-overlay/internal/overlay"
+)
+
+// DatasetSource marks lyrics loaded from an offline dataset
+const DatasetSource = "Dataset"
+
+// DatasetEntry is one song in an offline lyrics dataset
+type DatasetEntry struct {
+	Artist string              `json:"artist"`
+	Title  string              `json:"title"`
+	Lyrics *overlay.LyricsData `json:"lyrics"`
+}
+
+// LoadDataset preloads a JSON array of DatasetEntry into the cache, keyed by normalized
+// artist|title, so those songs work offline. Entries don't expire. It returns how many loaded.
+func (s *Service) LoadDataset(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read lyrics dataset: %w", err)
+	}
+
+	var entries []DatasetEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return 0, fmt.Errorf("failed to parse lyrics dataset: %w", err)
+	}
+
+	loaded := 0
+	for _, entry := range entries {
+		if entry.Artist == "" || entry.Title == "" || entry.Lyrics == nil {
+			continue
+		}
+		if len(entry.Lyrics.Lines) == 0 && !entry.Lyrics.IsInstrumental {
+			continue
+		}
+		entry.Lyrics.Source = DatasetSource
+		entry.Lyrics.TrackID = ""
+		s.cache.SetByKeyLongLived(normalizeForCache(cleanArtist(entry.Artist), entry.Title), entry.Lyrics)
+		loaded++
+	}
+	return loaded, nil
+}
@@ -0,0 +1,86 @@
+package lyrics
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	// providerFailureThreshold is how many consecutive failures demote a provider
+	providerFailureThreshold = 3
+	// providerCooldown is how long a demoted provider is skipped before it is tried again
+	providerCooldown = 5 * time.Minute
+)
+
+// ProviderHealth reports a provider's recent reliability
+type ProviderHealth struct {
+	Name                string    `json:"name"`
+	Healthy             bool      `json:"healthy"` // False while the provider is demoted
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	DisabledUntil       time.Time `json:"disabled_until,omitempty"`
+}
+
+// providerState tracks failures for a single provider
+type providerState struct {
+	consecutiveFailures int
+	lastError           string
+	disabledUntil       time.Time
+}
+
+// providerDemoted reports whether the named provider is in its failure cooldown
+func (s *Service) providerDemoted(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, ok := s.providerStates[name]
+	return ok && s.now().Before(state.disabledUntil)
+}
+
+// recordProviderResult updates the provider's failure streak. "No lyrics" answers count as
+// successes since the provider responded; only errors and timeouts count as failures.
+func (s *Service) recordProviderResult(name string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.providerStates[name]
+	if !ok {
+		state = &providerState{}
+		s.providerStates[name] = state
+	}
+
+	if err == nil || errors.Is(err, ErrNoLyrics) {
+		state.consecutiveFailures = 0
+		state.disabledUntil = time.Time{}
+		return
+	}
+
+	state.consecutiveFailures++
+	state.lastError = err.Error()
+	if state.consecutiveFailures >= providerFailureThreshold {
+		state.disabledUntil = s.now().Add(providerCooldown)
+		log.Printf("Lyrics: provider %s failed %d times in a row, skipping it for %v", name, state.consecutiveFailures, providerCooldown)
+	}
+}
+
+// ProviderHealth returns the health of each provider, in lookup order
+func (s *Service) ProviderHealth() []ProviderHealth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	health := make([]ProviderHealth, 0, len(s.providers))
+	for _, provider := range s.providers {
+		entry := ProviderHealth{Name: provider.GetName(), Healthy: true}
+		if state, ok := s.providerStates[entry.Name]; ok {
+			entry.ConsecutiveFailures = state.consecutiveFailures
+			entry.LastError = state.lastError
+			if now.Before(state.disabledUntil) {
+				entry.Healthy = false
+				entry.DisabledUntil = state.disabledUntil
+			}
+		}
+		health = append(health, entry)
+	}
+	return health
+}
@@ -1,7 +1,19 @@
 package lyrics
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"lyrics-overlay/internal/config"
+	"lyrics-overlay/internal/overlay"
 )
 
 func TestParseSyncedLyrics(t *testing.T) {
@@ -45,6 +57,12 @@ func TestNormalizeTitle(t *testing.T) {
 		{"Title - Radio Edit", "title"},
 		{"Song (Remix)", "song"},
 		{"Track - Remaster", "track"},
+		{"Caf\u00e9", "cafe"},                     // Composed é
+		{"Cafe\u0301", "cafe"},                    // e + combining acute
+		{"Beyonc\u00e9 (feat. Jay-Z)", "beyonce"}, // Accent plus feature suffix
+		{"\uff33\uff4f\uff4e\uff47", "song"},      // Full-width letters
+		{"Ni\u00f1o", "nino"},
+		{"\u30ac\u30e9\u30b9", "\u30ac\u30e9\u30b9"}, // Kana keep their dakuten
 	}
 
 	for _, tc := range tests {
@@ -55,6 +73,128 @@ func TestNormalizeTitle(t *testing.T) {
 	}
 }
 
+func TestNormalizeForCache_DistinctScripts(t *testing.T) {
+	// Titles that differ only by a non-Latin mark are different songs
+	pairs := [][2]string{
+		{"\u30ac\u30e9\u30b9", "\u30ab\u30e9\u30b9"}, // ガラス / カラス
+		{"\u3056\u308b", "\u3055\u308b"},             // ざる / さる
+		{"\uac08\ube44", "\uce7c\ube44"},             // 갈비 / 칼비
+		{"\ub2ec", "\ub2ed"},                         // 달 / 닭
+	}
+	for _, pair := range pairs {
+		if a, b := normalizeForCache("Artist", pair[0]), normalizeForCache("Artist", pair[1]); a == b {
+			t.Errorf("normalizeForCache(%q) and (%q) both = %q; want distinct keys", pair[0], pair[1], a)
+		}
+	}
+}
+
+func TestParseSyncedLyrics_DuplicatePairs(t *testing.T) {
+	raw := `[00:10.00]Chorus line
+[00:10.00]Chorus line
+[00:12.00]Verse line
+[00:20.00]Chorus line`
+
+	lines := ParseSyncedLyrics(raw)
+
+	// The exact duplicate is dropped; the chorus repeated later is kept
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %+v", len(lines), lines)
+	}
+	if lines[0].Timestamp != 10000 || lines[2].Timestamp != 20000 || lines[2].Text != "Chorus line" {
+		t.Errorf("Unexpected lines: %+v", lines)
+	}
+}
+
+func TestParseSyncedLyrics_WordTimings(t *testing.T) {
+	raw := `[00:10.00]<00:10.00>Hello <00:10.50>big <00:11.00>world<00:12.00>
+[00:13.00]Plain line`
+
+	lines := ParseSyncedLyrics(raw)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	if lines[0].Text != "Hello big world" {
+		t.Errorf("Text = %q; want %q", lines[0].Text, "Hello big world")
+	}
+	wantWords := []overlay.LyricsWord{{Text: "Hello", Timestamp: 10000}, {Text: "big", Timestamp: 10500}, {Text: "world", Timestamp: 11000, End: 12000}}
+	if len(lines[0].Words) != len(wantWords) {
+		t.Fatalf("Words = %+v; want %+v", lines[0].Words, wantWords)
+	}
+	for i, want := range wantWords {
+		if lines[0].Words[i] != want {
+			t.Errorf("Word %d = %+v; want %+v", i, lines[0].Words[i], want)
+		}
+	}
+	if lines[1].Words != nil {
+		t.Errorf("Plain line should have no word timings, got %+v", lines[1].Words)
+	}
+}
+
+func TestDisplayInfo_WordTimingsFromEnhancedLRC(t *testing.T) {
+	raw := `[00:10.00]<00:10.00>Hello <00:10.50>big <00:11.00>world<00:12.00>
+[00:13.00]Plain line
+[00:16.00]End`
+
+	configSvc, err := config.NewWithPath(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("config.NewWithPath failed: %v", err)
+	}
+	configSvc.Get().Overlay.WordTiming = true
+	overlaySvc, err := overlay.New(configSvc)
+	if err != nil {
+		t.Fatalf("overlay.New failed: %v", err)
+	}
+	overlaySvc.SetCurrentLyrics(&overlay.LyricsData{Source: "Test", IsSynced: true, Lines: ParseSyncedLyrics(raw)})
+
+	tests := []struct {
+		name     string
+		progress int64
+		want     []overlay.WordTiming
+	}{
+		{"timed words end at the next word and the closing tag", 10200, []overlay.WordTiming{
+			{Text: "Hello", StartMs: 10000, EndMs: 10500},
+			{Text: "big", StartMs: 10500, EndMs: 11000},
+			{Text: "world", StartMs: 11000, EndMs: 12000},
+		}},
+		{"plain line is one word spanning the line", 14000, []overlay.WordTiming{
+			{Text: "Plain line", StartMs: 13000, EndMs: 16000},
+		}},
+	}
+
+	for _, tt := range tests {
+		overlaySvc.SetCurrentTrack(&overlay.TrackInfo{ID: "track", Duration: 200000, Progress: tt.progress, UpdatedAt: time.Now()})
+		words := overlaySvc.GetDisplayInfo().Words
+		if len(words) != len(tt.want) {
+			t.Errorf("%s: Words = %+v; want %+v", tt.name, words, tt.want)
+			continue
+		}
+		for i, want := range tt.want {
+			if words[i] != want {
+				t.Errorf("%s: word %d = %+v; want %+v", tt.name, i, words[i], want)
+			}
+		}
+	}
+}
+
+func TestParseSyncedLyrics_Bilingual(t *testing.T) {
+	raw := `[00:10.00]Hola mundo
+[00:10.00]Hello world
+[00:14.00]Adiós
+[00:14.00]Goodbye`
+
+	lines := ParseSyncedLyrics(raw)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 merged lines, got %d: %+v", len(lines), lines)
+	}
+	if lines[0].Text != "Hola mundo" || lines[0].SecondaryText != "Hello world" {
+		t.Errorf("Line 0 = %q / %q; want %q / %q", lines[0].Text, lines[0].SecondaryText, "Hola mundo", "Hello world")
+	}
+	if lines[1].Text != "Adiós" || lines[1].SecondaryText != "Goodbye" {
+		t.Errorf("Line 1 = %q / %q; want %q / %q", lines[1].Text, lines[1].SecondaryText, "Adiós", "Goodbye")
+	}
+}
+
 func TestParseSyncedLyrics_WithMetadata(t *testing.T) {
 	raw := `[ti:Test Song]
 [ar:Test Artist]
@@ -144,15 +284,478 @@ func TestNormalizeTitle_Complex(t *testing.T) {
 }
 
 func TestLRCLibProvider_GetName(t *testing.T) {
-	provider := NewLRCLibProvider(nil)
+	provider := NewLRCLibProvider(nil, "")
 	if provider.GetName() != "LRCLIB" {
 		t.Errorf("Expected provider name 'LRCLIB', got %q", provider.GetName())
 	}
 }
 
+func TestNewLRCLibProvider_BaseURL(t *testing.T) {
+	if got := NewLRCLibProvider(nil, "").baseURL; got != DefaultLRCLibBaseURL {
+		t.Errorf("Empty base URL = %q; want %q", got, DefaultLRCLibBaseURL)
+	}
+	if got := NewLRCLibProvider(nil, "http://localhost:3000/api/").baseURL; got != "http://localhost:3000/api" {
+		t.Errorf("Trailing slash not trimmed: %q", got)
+	}
+}
+
+func TestValidateBaseURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://lrclib.net/api", false},
+		{"http://192.168.1.10:3000/api", false},
+		{"lrclib.local/api", true},
+		{"ftp://lrclib.local/api", true},
+		{"https:///api", true},
+		{"https://lrclib.local/api?key=1", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateBaseURL(tt.url); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBaseURL(%q) error = %v; wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
 func TestDemoProvider_GetName(t *testing.T) {
 	provider := NewDemoProvider()
 	if provider.GetName() != "Demo" {
 		t.Errorf("Expected provider name 'Demo', got %q", provider.GetName())
 	}
 }
+
+func TestTrackToLyricsData_Instrumental(t *testing.T) {
+	provider := NewLRCLibProvider(nil, "")
+
+	data := provider.trackToLyricsData(&lrcLibTrack{
+		ID:           1,
+		TrackName:    "Interlude",
+		ArtistName:   "Artist",
+		Instrumental: true,
+	}, false)
+
+	if data == nil {
+		t.Fatal("Expected instrumental lyrics data, got nil")
+	}
+	if !data.IsInstrumental {
+		t.Error("Expected IsInstrumental to be set")
+	}
+	if data.Source != "LRCLIB" {
+		t.Errorf("Expected source 'LRCLIB', got %q", data.Source)
+	}
+	if len(data.Lines) != 0 {
+		t.Errorf("Expected no lines, got %d", len(data.Lines))
+	}
+}
+
+func TestFilterByDuration(t *testing.T) {
+	results := []lrcLibTrack{
+		{ID: 1, Duration: 240},
+		{ID: 2, Duration: 181},
+		{ID: 3},
+	}
+
+	filtered := filterByDuration(results, 180)
+	if len(filtered) != 1 || filtered[0].ID != 2 {
+		t.Errorf("filterByDuration = %+v; want only ID 2", filtered)
+	}
+
+	// Nothing close enough: keep everything rather than losing all candidates
+	if got := filterByDuration(results, 60); len(got) != len(results) {
+		t.Errorf("filterByDuration kept %d results; want %d", len(got), len(results))
+	}
+}
+
+func TestCleanArtist(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Daft Punk - Topic", "Daft Punk"},
+		{"Daft Punk - topic", "Daft Punk"},
+		{"AdeleVEVO", "Adele"},
+		{"Imagine Dragons - Official", "Imagine Dragons"},
+		{"Artist (Official)", "Artist"},
+		// Legitimate names stay intact
+		{"Topic", "Topic"},
+		{"Vevo", "Vevo"},
+		{"Panic! at the Disco", "Panic! at the Disco"},
+		{"Crosby, Stills & Nash", "Crosby, Stills & Nash"},
+		{"The Topic Band", "The Topic Band"},
+	}
+
+	for _, tc := range tests {
+		if got := cleanArtist(tc.input); got != tc.want {
+			t.Errorf("cleanArtist(%q) = %q; want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestTrackToLyricsData_SourceURL(t *testing.T) {
+	l := NewLRCLibProvider(nil, "")
+
+	data := l.trackToLyricsData(&lrcLibTrack{ID: 42, PlainLyrics: "Hello"}, false)
+	if data == nil || data.SourceURL != "https://lrclib.net/lyrics/42" {
+		t.Fatalf("SourceURL = %+v; want the lrclib.net page", data)
+	}
+
+	if data := l.trackToLyricsData(&lrcLibTrack{PlainLyrics: "Hello"}, false); data.SourceURL != "" {
+		t.Errorf("SourceURL without an ID = %q; want empty", data.SourceURL)
+	}
+
+	selfHosted := NewLRCLibProvider(nil, "http://localhost:3000/api")
+	if data := selfHosted.trackToLyricsData(&lrcLibTrack{ID: 42, PlainLyrics: "Hello"}, false); data.SourceURL != "" {
+		t.Errorf("SourceURL for a self-hosted instance = %q; want empty", data.SourceURL)
+	}
+}
+
+func TestTextToLyricsLines_SectionHeaders(t *testing.T) {
+	lines := textToLyricsLines("[Verse 1: Artist]\nFirst line\n[Chorus]\nHook [yeah]\n")
+
+	want := []struct {
+		text      string
+		isSection bool
+	}{
+		{"[Verse 1: Artist]", true},
+		{"First line", false},
+		{"[Chorus]", true},
+		{"Hook [yeah]", false},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d: %+v", len(want), len(lines), lines)
+	}
+	for i, w := range want {
+		if lines[i].Text != w.text || lines[i].IsSection != w.isSection {
+			t.Errorf("Line %d = %q (section %v); want %q (section %v)", i, lines[i].Text, lines[i].IsSection, w.text, w.isSection)
+		}
+	}
+}
+
+func TestLRCLibProvider_HTMLErrorPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<html><body><h1>502 Bad Gateway</h1></body></html>")
+	}))
+	defer server.Close()
+
+	provider := NewLRCLibProvider(server.Client(), server.URL)
+
+	_, err := provider.search(context.Background(), "Artist", "Title")
+	if err == nil || !strings.Contains(err.Error(), "unexpected response") {
+		t.Errorf("search error = %v; want an unexpected response error", err)
+	}
+	if errors.Is(err, ErrNoLyrics) {
+		t.Error("An HTML error page should not be reported as no lyrics")
+	}
+}
+
+func TestDecodeLRCLibJSON(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+		Body:   io.NopCloser(strings.NewReader(`[{"id": 7, "trackName": "Song"}]`)),
+	}
+	var results []lrcLibTrack
+	if err := decodeLRCLibJSON(resp, &results); err != nil {
+		t.Fatalf("decodeLRCLibJSON failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != 7 {
+		t.Errorf("Decoded %+v; want one track with ID 7", results)
+	}
+}
+
+func TestProviderRequestHeaders(t *testing.T) {
+	var gotAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "[]")
+	}))
+	defer server.Close()
+
+	SetContact("me@example.com")
+	defer SetContact("")
+
+	provider := NewLRCLibProvider(server.Client(), server.URL)
+	if _, err := provider.search(context.Background(), "Artist", "Title"); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	want := "SpotLy/" + appVersion + " (me@example.com)"
+	if gotAgent != want {
+		t.Errorf("User-Agent = %q; want %q", gotAgent, want)
+	}
+}
+
+func TestLRCLibProvider_MatchKind(t *testing.T) {
+	tests := []struct {
+		name      string
+		get       string // body of /get, empty for 404
+		search    string // body of /search with track_name
+		query     string // body of /search?q=
+		wantKind  string
+		wantScore int
+	}{
+		{
+			name:      "direct get",
+			get:       `{"id": 1, "trackName": "Song", "artistName": "Artist", "syncedLyrics": "[00:01.00]Hi"}`,
+			wantKind:  overlay.MatchExact,
+			wantScore: 8,
+		},
+		{
+			name:      "scored search",
+			search:    `[{"id": 2, "trackName": "Song (Live)", "artistName": "Artist", "plainLyrics": "Hi"}]`,
+			wantKind:  overlay.MatchFuzzy,
+			wantScore: 4,
+		},
+		{
+			name:      "loose query",
+			search:    `[]`,
+			query:     `[{"id": 3, "trackName": "Other", "artistName": "Someone", "plainLyrics": "Hi"}]`,
+			wantKind:  overlay.MatchFallback,
+			wantScore: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.URL.Path == "/get" && tt.get != "":
+				fmt.Fprint(w, tt.get)
+			case r.URL.Path == "/search" && r.URL.Query().Get("q") != "":
+				fmt.Fprint(w, tt.query)
+			case r.URL.Path == "/search":
+				fmt.Fprint(w, tt.search)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+
+		provider := NewLRCLibProvider(server.Client(), server.URL)
+		data, err := provider.SearchLyrics(context.Background(), "Artist", "Song")
+		server.Close()
+		if err != nil {
+			t.Errorf("%s: SearchLyrics failed: %v", tt.name, err)
+			continue
+		}
+		if data.MatchKind != tt.wantKind || data.MatchScore != tt.wantScore {
+			t.Errorf("%s: match = %q/%d; want %q/%d", tt.name, data.MatchKind, data.MatchScore, tt.wantKind, tt.wantScore)
+		}
+	}
+}
+
+func TestLooseTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Song (Bonus Track)", "Song"},
+		{"Song [Live] (2011 Remaster)", "Song"},
+		{"Song (Bonus Track) - 2011 Remaster", "Song"},
+		{"Song - Part Two", "Song - Part Two"},
+		{"(Intro)", "(Intro)"},
+		{"Plain", "Plain"},
+	}
+	for _, tt := range tests {
+		if got := looseTitle(tt.title); got != tt.want {
+			t.Errorf("looseTitle(%q) = %q; want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestLRCLibProvider_LoosenedQueries(t *testing.T) {
+	tests := []struct {
+		name        string
+		title       string
+		matchQ      string // the only free-text query LRCLIB answers
+		wantQueries []string
+	}{
+		{"stripped title with artist", "Song (Bonus Track)", "Song Artist", []string{"Song Artist"}},
+		{"stripped title alone", "Song (Bonus Track)", "Song", []string{"Song Artist", "Song"}},
+		{"raw title with brackets", "Song (Bonus Track)", "Song (Bonus Track)", []string{"Song Artist", "Song", "Song (Bonus Track)"}},
+	}
+
+	for _, tt := range tests {
+		var queries []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			q := r.URL.Query().Get("q")
+			switch {
+			case r.URL.Path != "/search":
+				http.NotFound(w, r)
+			case q == "":
+				fmt.Fprint(w, "[]")
+			case q == tt.matchQ:
+				queries = append(queries, q)
+				fmt.Fprint(w, `[{"id": 9, "trackName": "Song", "artistName": "Artist", "plainLyrics": "Hi"}]`)
+			default:
+				queries = append(queries, q)
+				fmt.Fprint(w, "[]")
+			}
+		}))
+
+		provider := NewLRCLibProvider(server.Client(), server.URL)
+		data, err := provider.SearchLyrics(context.Background(), "Artist", tt.title)
+		server.Close()
+		if err != nil {
+			t.Errorf("%s: SearchLyrics failed: %v", tt.name, err)
+			continue
+		}
+		if len(data.Lines) == 0 {
+			t.Errorf("%s: expected lyrics from the loosened query", tt.name)
+		}
+		if strings.Join(queries, "|") != strings.Join(tt.wantQueries, "|") {
+			t.Errorf("%s: queries = %q; want %q", tt.name, queries, tt.wantQueries)
+		}
+	}
+}
+
+func TestDemoProvider_SyncedDemo(t *testing.T) {
+	demo := NewDemoProvider().SyncedDemo()
+	if !demo.IsSynced || len(demo.Lines) < 5 {
+		t.Fatalf("SyncedDemo = %+v; want several synced lines", demo)
+	}
+	last := demo.Lines[len(demo.Lines)-1]
+	if last.Timestamp >= DemoDurationMs {
+		t.Errorf("Last demo line at %dms; want before the %dms loop end", last.Timestamp, DemoDurationMs)
+	}
+}
+
+func TestParseSyncedLyrics_TimestampForms(t *testing.T) {
+	tests := []struct {
+		raw    string
+		wantMs int64
+	}{
+		{"[00:12]No fraction", 12000},
+		{"[00:12.3]One digit", 12300},
+		{"[00:12.34]Two digits", 12340},
+		{"[00:12.345]Three digits", 12345},
+		{"[5:07.00]Single-digit minutes", 307000},
+		{"[100:05.00]Wide minutes", 6005000},
+		{"[1:02:03.45]With hours", 3723450},
+		{"[1:02:03]Hours without fraction", 3723000},
+	}
+
+	for _, tc := range tests {
+		lines := ParseSyncedLyrics(tc.raw)
+		if len(lines) != 1 {
+			t.Errorf("ParseSyncedLyrics(%q) returned %d lines; want 1", tc.raw, len(lines))
+			continue
+		}
+		if lines[0].Timestamp != tc.wantMs {
+			t.Errorf("ParseSyncedLyrics(%q) time = %d; want %d", tc.raw, lines[0].Timestamp, tc.wantMs)
+		}
+	}
+}
+
+func TestNormalizeCacheKey(t *testing.T) {
+	tests := []struct {
+		artist, title string
+		want          string
+	}{
+		{"Artist", "Song", "artist|song"},
+		{"Artist - Topic", "Song (feat. Other)", "artist|song"},
+		{"ArtistVEVO", "Song - Radio Edit", "artist|song"},
+	}
+
+	for _, tc := range tests {
+		if got := NormalizeCacheKey(tc.artist, tc.title); got != tc.want {
+			t.Errorf("NormalizeCacheKey(%q, %q) = %q; want %q", tc.artist, tc.title, got, tc.want)
+		}
+	}
+}
+
+func TestCheckConnectivity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound) // Any answer means the host is reachable
+	}))
+	defer server.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+
+	s := NewWithProviders(nil, NewLRCLibProvider(server.Client(), server.URL), NewDemoProvider())
+	checks := s.CheckConnectivity(context.Background(), DefaultCheckTimeout)
+	if len(checks) != 1 || checks[0].Name != "LRCLIB" || !checks[0].Reachable {
+		t.Fatalf("Expected one reachable LRCLIB check, got %+v", checks)
+	}
+
+	s = NewWithProviders(nil, NewLRCLibProvider(down.Client(), down.URL))
+	checks = s.CheckConnectivity(context.Background(), DefaultCheckTimeout)
+	if len(checks) != 1 || checks[0].Reachable || checks[0].Error == "" {
+		t.Errorf("Expected an unreachable LRCLIB check with an error, got %+v", checks)
+	}
+
+	if _, err := s.SetProviderEnabled("LRCLIB", false); err != nil {
+		t.Fatalf("SetProviderEnabled failed: %v", err)
+	}
+	if checks := s.CheckConnectivity(context.Background(), DefaultCheckTimeout); len(checks) != 0 {
+		t.Errorf("Disabled providers should not be checked, got %+v", checks)
+	}
+}
+
+func TestLRCLibProvider_PreferPlainOnLowConfidence(t *testing.T) {
+	record := &lrcLibTrack{
+		ID:           1,
+		Duration:     200,
+		PlainLyrics:  "Hello",
+		SyncedLyrics: "[00:01.00]Hello",
+	}
+	tests := []struct {
+		name       string
+		enabled    bool
+		kind       string
+		durationMs int64
+		wantSynced bool
+	}{
+		{"disabled keeps synced", false, overlay.MatchFuzzy, 200000, true},
+		{"exact match keeps synced", true, overlay.MatchExact, 200000, true},
+		{"exact match with unknown duration keeps synced", true, overlay.MatchExact, 0, true},
+		{"fuzzy match uses plain", true, overlay.MatchFuzzy, 200000, false},
+		{"fallback match uses plain", true, overlay.MatchFallback, 200000, false},
+		{"duration mismatch uses plain", true, overlay.MatchExact, 230000, false},
+	}
+
+	for _, tt := range tests {
+		provider := NewLRCLibProvider(nil, "")
+		provider.SetPreferPlainOnLowConfidence(tt.enabled)
+		data := provider.trackToLyricsData(record, provider.preferPlainFor(tt.kind, record, tt.durationMs))
+		if data == nil || data.IsSynced != tt.wantSynced {
+			t.Errorf("%s: got %+v; want synced %v", tt.name, data, tt.wantSynced)
+		}
+	}
+
+	// Without plain lyrics the synced ones are still used
+	syncedOnly := &lrcLibTrack{ID: 2, SyncedLyrics: "[00:01.00]Hello"}
+	if data := lrcLibLyrics(syncedOnly, true); data == nil || !data.IsSynced {
+		t.Errorf("Expected synced lyrics when no plain variant exists, got %+v", data)
+	}
+}
+
+func TestLyricsMetadata(t *testing.T) {
+	l := NewLRCLibProvider(nil, "")
+	data := l.trackToLyricsData(&lrcLibTrack{ID: 7, AlbumName: "Greatest Hits", Duration: 201.5, PlainLyrics: "Hello"}, false)
+	if data.MatchedAlbum != "Greatest Hits" || data.MatchedDurationMs != 201500 {
+		t.Fatalf("Matched album/duration = %q/%d; want Greatest Hits/201500", data.MatchedAlbum, data.MatchedDurationMs)
+	}
+
+	tests := []struct {
+		album    string
+		wantNote bool
+	}{
+		{"Greatest Hits", false},
+		{"Greatest Hits (Deluxe Edition)", false},
+		{"Live at Wembley", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		meta := Metadata(data, &overlay.TrackInfo{Album: tt.album})
+		if (meta.Note != "") != tt.wantNote {
+			t.Errorf("Album %q: note = %q; want note %v", tt.album, meta.Note, tt.wantNote)
+		}
+	}
+	if meta := Metadata(data, nil); meta.Note != "" || meta.MatchedAlbum != "Greatest Hits" {
+		t.Errorf("Metadata without a track = %+v", meta)
+	}
+}
@@ -0,0 +1,52 @@
+package lyrics
+
+import (
+	"fmt"
+
+	"lyrics-overlay/internal/overlay"
+)
+
+// albumMismatchThreshold is the album name similarity (0-1) below which a match is flagged
+const albumMismatchThreshold = 0.5
+
+// LyricsMetadata describes which record the lyrics were matched to, for checking the match
+type LyricsMetadata struct {
+	Source            string  `json:"source"`
+	SourceURL         string  `json:"source_url,omitempty"`
+	MatchedArtist     string  `json:"matched_artist,omitempty"`
+	MatchedTitle      string  `json:"matched_title,omitempty"`
+	MatchedAlbum      string  `json:"matched_album,omitempty"`
+	MatchedDurationMs int64   `json:"matched_duration_ms,omitempty"`
+	MatchKind         string  `json:"match_kind,omitempty"`
+	MatchConfidence   float64 `json:"match_confidence"`
+	// Set when the matched record looks like a different release than the playing track
+	Note string `json:"note,omitempty"`
+}
+
+// Metadata summarizes what data was matched to, noting when its album differs significantly
+// from the track's (a common sign of a wrong match). track may be nil.
+func Metadata(data *overlay.LyricsData, track *overlay.TrackInfo) LyricsMetadata {
+	meta := LyricsMetadata{
+		Source:            data.Source,
+		SourceURL:         data.SourceURL,
+		MatchedArtist:     data.MatchedArtist,
+		MatchedTitle:      data.MatchedTitle,
+		MatchedAlbum:      data.MatchedAlbum,
+		MatchedDurationMs: data.MatchedDurationMs,
+		MatchKind:         data.MatchKind,
+		MatchConfidence:   data.MatchConfidence,
+	}
+	if track != nil && albumsDiffer(data.MatchedAlbum, track.Album) {
+		meta.Note = fmt.Sprintf("Matched album %q differs from the playing album %q", data.MatchedAlbum, track.Album)
+	}
+	return meta
+}
+
+// albumsDiffer reports whether two album names are known and clearly not the same release,
+// ignoring edition suffixes like "(Deluxe)"
+func albumsDiffer(matched, playing string) bool {
+	if matched == "" || playing == "" {
+		return false
+	}
+	return similarity(normalizeString(looseTitle(matched)), normalizeString(looseTitle(playing))) < albumMismatchThreshold
+}
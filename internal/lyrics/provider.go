@@ -1,16 +1,25 @@
 package lyrics
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 
 	"lyrics-overlay/internal/cache"
 	"lyrics-overlay/internal/overlay"
@@ -18,29 +27,74 @@ import (
 
 // LyricsProvider defines the interface for lyrics sources
 type LyricsProvider interface {
-	SearchLyrics(artist, title string) (*overlay.LyricsData, error)
+	SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error)
 	GetName() string
 }
 
+// TrackQuery holds everything known about the track being looked up
+type TrackQuery struct {
+	TrackID    string
+	Artist     string
+	Title      string
+	Album      string
+	DurationMs int64
+	ISRC       string // International Standard Recording Code, identifies the exact recording
+	Country    string // ISO 3166-1 alpha-2 country of the user's account, "" when unknown
+}
+
+// TrackProvider is implemented by providers that can use full track metadata (ISRC, duration)
+// instead of only artist and title
+type TrackProvider interface {
+	SearchTrack(ctx context.Context, query TrackQuery) (*overlay.LyricsData, error)
+}
+
+// Errors returned by GetLyrics so callers can tell a missing track from a network failure
+var (
+	// ErrNoLyrics means the providers answered but had no lyrics for the track
+	ErrNoLyrics = errors.New("no lyrics found")
+	// ErrProviderUnavailable means no provider could be reached (network error, timeout, bad status)
+	ErrProviderUnavailable = errors.New("lyrics provider unavailable")
+	// ErrInstrumental means the track is known to have no lyrics; the instrumental data is still returned
+	ErrInstrumental = errors.New("track is instrumental")
+)
+
+const (
+	// defaultProviderTimeout bounds a single provider lookup
+	defaultProviderTimeout = 8 * time.Second
+	// defaultTotalTimeout bounds a full lookup across all providers
+	defaultTotalTimeout = 20 * time.Second
+	// DefaultMaxConcurrentFetches bounds simultaneous provider lookups
+	DefaultMaxConcurrentFetches = 2
+)
+
 // Service manages lyrics fetching and caching
 type Service struct {
-	providers []LyricsProvider
-	cache     *cache.Service
-	client    *http.Client
+	providers        []LyricsProvider
+	cache            *cache.Service
+	client           *http.Client
+	mu               sync.RWMutex
+	providerTimeouts map[string]time.Duration // Per-provider overrides keyed by provider name
+	totalTimeout     time.Duration
+	observer         LookupObserver
+	providerStates   map[string]*providerState // Failure streaks keyed by provider name
+	now              func() time.Time
+	fetchSlots       chan struct{}        // Semaphore bounding concurrent provider lookups
+	countrySource    func() string        // Reports the user's country for region-aware providers
+	disabled         map[string]bool      // Providers the user turned off, keyed by provider name
+	misses           map[string]time.Time // Normalized keys no provider had lyrics for, and when
 }
 
-// New creates a new lyrics service
-func New(cacheSvc *cache.Service) *Service {
-	service := &Service{
-		providers: make([]LyricsProvider, 0),
-		cache:     cacheSvc,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+// New creates a new lyrics service. lrclibBaseURL points LRCLIB at a self-hosted instance;
+// empty uses the public one. client is used for all provider requests (nil for the default),
+// letting tests stub the network.
+func New(cacheSvc *cache.Service, lrclibBaseURL string, client *http.Client) *Service {
+	service := NewWithProviders(cacheSvc)
+	if client != nil {
+		service.client = client
 	}
 
 	// Add LRCLIB provider first (often returns synced lyrics)
-	lrclibProvider := NewLRCLibProvider(service.client)
+	lrclibProvider := NewLRCLibProvider(service.client, lrclibBaseURL)
 	service.AddProvider(lrclibProvider)
 
 	// Add demo provider as a fallback
@@ -50,20 +104,199 @@ func New(cacheSvc *cache.Service) *Service {
 	return service
 }
 
+// NewWithProviders creates a lyrics service that queries the given providers in order
+func NewWithProviders(cacheSvc *cache.Service, providers ...LyricsProvider) *Service {
+	return &Service{
+		providers: append(make([]LyricsProvider, 0, len(providers)), providers...),
+		cache:     cacheSvc,
+		client: &http.Client{
+			Timeout: 30 * time.Second, // Backstop; per-provider deadlines come from the context
+		},
+		providerTimeouts: make(map[string]time.Duration),
+		totalTimeout:     defaultTotalTimeout,
+		providerStates:   make(map[string]*providerState),
+		now:              time.Now,
+		fetchSlots:       make(chan struct{}, DefaultMaxConcurrentFetches),
+		disabled:         make(map[string]bool),
+		misses:           make(map[string]time.Time),
+	}
+}
+
 // AddProvider adds a lyrics provider
 func (s *Service) AddProvider(provider LyricsProvider) {
 	s.providers = append(s.providers, provider)
 }
 
-// GetLyrics fetches lyrics for a track, checking cache first
-func (s *Service) GetLyrics(trackID, artist, title string) (*overlay.LyricsData, error) {
-	// Check cache first by track ID
+// ProviderInfo describes a registered provider and whether lookups use it
+type ProviderInfo struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Providers lists the registered providers in lookup order
+func (s *Service) Providers() []ProviderInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	infos := make([]ProviderInfo, 0, len(s.providers))
+	for _, provider := range s.providers {
+		infos = append(infos, ProviderInfo{Name: provider.GetName(), Enabled: !s.disabled[provider.GetName()]})
+	}
+	return infos
+}
+
+// SetProviderEnabled turns a provider on or off for subsequent lookups. The name is matched
+// case-insensitively; the registered name is returned.
+func (s *Service) SetProviderEnabled(name string, enabled bool) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, provider := range s.providers {
+		if strings.EqualFold(provider.GetName(), name) {
+			if enabled {
+				delete(s.disabled, provider.GetName())
+			} else {
+				s.disabled[provider.GetName()] = true
+			}
+			return provider.GetName(), nil
+		}
+	}
+	return "", fmt.Errorf("unknown lyrics provider %q", name)
+}
+
+// providerEnabled reports whether the user has left a provider on
+func (s *Service) providerEnabled(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !s.disabled[name]
+}
+
+// SetProviderTimeout sets the per-request deadline for the named provider (0 restores the default)
+func (s *Service) SetProviderTimeout(name string, timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timeout <= 0 {
+		delete(s.providerTimeouts, name)
+		return
+	}
+	s.providerTimeouts[name] = timeout
+}
+
+// SetTotalTimeout sets the overall deadline for a lookup across all providers (0 restores the default)
+func (s *Service) SetTotalTimeout(timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timeout <= 0 {
+		timeout = defaultTotalTimeout
+	}
+	s.totalTimeout = timeout
+}
+
+// SetMaxConcurrentFetches sets how many provider lookups may run at once (0 restores the default).
+// Lookups already holding a slot finish against the previous limit.
+func (s *Service) SetMaxConcurrentFetches(n int) {
+	if n <= 0 {
+		n = DefaultMaxConcurrentFetches
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetchSlots = make(chan struct{}, n)
+}
+
+// acquireFetchSlot waits for a free lookup slot, returning the release function
+func (s *Service) acquireFetchSlot(ctx context.Context) (func(), error) {
+	s.mu.RLock()
+	slots := s.fetchSlots
+	s.mu.RUnlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// providerTimeout returns the deadline to apply to the named provider
+func (s *Service) providerTimeout(name string) time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if timeout, ok := s.providerTimeouts[name]; ok {
+		return timeout
+	}
+	return defaultProviderTimeout
+}
+
+// searchProvider runs a single provider lookup under its own deadline
+func (s *Service) searchProvider(ctx context.Context, provider LyricsProvider, query TrackQuery) (*overlay.LyricsData, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.providerTimeout(provider.GetName()))
+	defer cancel()
+	if trackProvider, ok := provider.(TrackProvider); ok {
+		return trackProvider.SearchTrack(ctx, query)
+	}
+	return provider.SearchLyrics(ctx, query.Artist, query.Title)
+}
+
+// GetLyrics fetches lyrics for a track, checking cache first. It returns ErrNoLyrics when
+// no provider has the track, ErrProviderUnavailable when none could be reached, and the
+// instrumental data together with ErrInstrumental for tracks without lyrics.
+func (s *Service) GetLyrics(ctx context.Context, trackID, artist, title string) (*overlay.LyricsData, error) {
+	return s.lookup(ctx, TrackQuery{TrackID: trackID, Artist: artist, Title: title})
+}
+
+// SearchLyrics looks up lyrics for an artist and title without a playing track, e.g. for a
+// search box. It uses and populates the cache by normalized key only.
+func (s *Service) SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error) {
+	return s.lookup(ctx, TrackQuery{Artist: artist, Title: title})
+}
+
+// GetLyricsForTrack is like GetLyrics but also uses the track's ISRC, album and duration
+// for exact matching where providers support it
+func (s *Service) GetLyricsForTrack(ctx context.Context, track *overlay.TrackInfo) (*overlay.LyricsData, error) {
+	artist := ""
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0]
+	}
+	return s.lookup(ctx, TrackQuery{
+		TrackID:    track.ID,
+		Artist:     artist,
+		Title:      track.Name,
+		Album:      track.Album,
+		DurationMs: track.Duration,
+		ISRC:       track.ISRC,
+	})
+}
+
+// isrcCacheKey returns the cache key for an ISRC, or "" when unknown
+func isrcCacheKey(isrc string) string {
+	if isrc == "" {
+		return ""
+	}
+	return "isrc:" + strings.ToUpper(strings.TrimSpace(isrc))
+}
+
+// lookup resolves lyrics from the cache or providers
+func (s *Service) lookup(ctx context.Context, query TrackQuery) (*overlay.LyricsData, error) {
+	query.Artist = cleanArtist(query.Artist)
+	if query.Country == "" {
+		query.Country = s.country()
+	}
+	trackID, artist, title := query.TrackID, query.Artist, query.Title
+
+	// The ISRC identifies the exact recording, so it takes priority over everything else
+	isrcKey := isrcCacheKey(query.ISRC)
+	if isrcKey != "" {
+		if lyrics := s.cache.GetByKey(isrcKey); lyrics != nil && !isPlaceholderSource(lyrics.Source) {
+			s.cacheByTrackID(trackID, lyrics)
+			return fromCache(lyrics), lyricsResultError(lyrics)
+		}
+	}
+
+	// Check cache by track ID
 	if lyrics := s.cache.GetByTrackID(trackID); lyrics != nil {
 		// Don't accept demo/info cache as final result
-		if strings.EqualFold(lyrics.Source, "Info") || strings.EqualFold(lyrics.Source, "Demo") {
+		if isPlaceholderSource(lyrics.Source) {
 			log.Printf("Lyrics cache hit is Info/Demo for %s - %s, ignoring and refetching", artist, title)
 		} else {
-			return lyrics, nil
+			return fromCache(lyrics), lyricsResultError(lyrics)
 		}
 	}
 
@@ -71,37 +304,326 @@ func (s *Service) GetLyrics(trackID, artist, title string) (*overlay.LyricsData,
 	normalizedKey := normalizeForCache(artist, title)
 	if lyrics := s.cache.GetByKey(normalizedKey); lyrics != nil {
 		// Cache hit with normalized key, also cache by track ID
-		if strings.EqualFold(lyrics.Source, "Info") || strings.EqualFold(lyrics.Source, "Demo") {
+		if isPlaceholderSource(lyrics.Source) {
 			log.Printf("Lyrics cache(key) is Info/Demo for %s - %s, ignoring and refetching", artist, title)
 		} else {
-			s.cache.SetByTrackID(trackID, lyrics)
-			return lyrics, nil
+			s.cacheByTrackID(trackID, lyrics)
+			return fromCache(lyrics), lyricsResultError(lyrics)
 		}
 	}
 
 	// No cache hit, fetch from providers
+	lyrics, err := s.searchProviders(ctx, query, normalizedKey, isrcKey)
+	s.notifyLookup(query, lyrics, err)
+
+	// Imports often carry "Artist - Title" in the title with a placeholder artist
+	if errors.Is(err, ErrNoLyrics) {
+		if splitArtist, splitTitle, ok := splitArtistFromTitle(query.Artist, query.Title); ok {
+			log.Printf("Lyrics: retrying %q as %s - %s", query.Title, splitArtist, splitTitle)
+			retry := query
+			retry.Artist, retry.Title = splitArtist, splitTitle
+			lyrics, err = s.lookup(ctx, retry)
+		}
+	}
+	if errors.Is(err, ErrNoLyrics) || (err == nil && lyrics != nil && isPlaceholderSource(lyrics.Source)) {
+		s.recordMiss(normalizedKey)
+	}
+	return lyrics, err
+}
+
+// genericArtists are placeholder artist names that carry no information
+var genericArtists = map[string]bool{
+	"":                true,
+	"unknown":         true,
+	"unknown artist":  true,
+	"various":         true,
+	"various artists": true,
+	"artist":          true,
+	"n/a":             true,
+}
+
+// titleVersionSuffixes are " - ..." title suffixes that describe a version, not a song title
+var titleVersionSuffixes = regexp.MustCompile(`(?i)^(\d{4}\s+)?(remaster(ed)?|live|radio edit|edit|remix|mix|version|acoustic|instrumental|demo|mono|stereo|single|bonus track|explicit|clean)\b`)
+
+// splitArtistFromTitle splits "Artist - Title" out of the title when the artist is a
+// placeholder. Hyphenated titles are left alone unless the left side looks like an artist and
+// the right side isn't a version marker such as "Remastered 2011" or "Live".
+func splitArtistFromTitle(artist, title string) (string, string, bool) {
+	if !genericArtists[strings.ToLower(strings.TrimSpace(artist))] {
+		return "", "", false
+	}
+
+	left, right, found := strings.Cut(title, " - ")
+	if !found {
+		return "", "", false
+	}
+	left, right = strings.TrimSpace(left), strings.TrimSpace(right)
+	if left == "" || right == "" || len(left) > 60 || genericArtists[strings.ToLower(left)] {
+		return "", "", false
+	}
+	if titleVersionSuffixes.MatchString(right) {
+		return "", "", false
+	}
+	return left, right, true
+}
+
+// searchProviders queries providers in order within the overall deadline, caching the result
+func (s *Service) searchProviders(ctx context.Context, query TrackQuery, normalizedKey, isrcKey string) (*overlay.LyricsData, error) {
+	trackID, artist, title := query.TrackID, query.Artist, query.Title
+
+	s.mu.RLock()
+	totalTimeout := s.totalTimeout
+	s.mu.RUnlock()
+	ctx, cancel := context.WithTimeout(ctx, totalTimeout)
+	defer cancel()
+
+	// Bound concurrent lookups so a burst of skips doesn't open dozens of connections
+	release, err := s.acquireFetchSlot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s - %s: %v", ErrProviderUnavailable, artist, title, err)
+	}
+	defer release()
+
+	// Remember whether any provider actually answered, so a network outage isn't reported as "no lyrics"
+	answered, failed := false, false
+	var mismatch *overlay.LyricsData // Lyrics rejected for overrunning the track
 	for _, provider := range s.providers {
+		if ctx.Err() != nil {
+			log.Printf("Lyrics: lookup deadline reached for %s - %s", artist, title)
+			failed = true
+			break
+		}
+		if !s.providerEnabled(provider.GetName()) {
+			continue
+		}
+		if s.providerDemoted(provider.GetName()) {
+			log.Printf("Lyrics: skipping demoted provider %s", provider.GetName())
+			failed = true
+			continue
+		}
 		log.Printf("Lyrics: trying provider %s for %s - %s", provider.GetName(), artist, title)
-		lyrics, err := provider.SearchLyrics(artist, title)
+		lyrics, err := s.searchProvider(ctx, provider, query)
+		if errors.Is(ctx.Err(), context.Canceled) {
+			// The caller gave up (e.g. the track changed); not the provider's fault
+			return nil, ctx.Err()
+		}
+		s.recordProviderResult(provider.GetName(), err)
 		if err != nil {
 			log.Printf("Lyrics: provider %s error: %v", provider.GetName(), err)
+			if errors.Is(err, ErrNoLyrics) {
+				answered = true
+			} else {
+				failed = true
+			}
 			continue // Try next provider
 		}
+		answered = true
+
+		if lyrics != nil && (len(lyrics.Lines) > 0 || lyrics.IsInstrumental) {
+			// Lyrics running well past the track are probably for another recording; let the
+			// next provider try, keeping these in case nothing better turns up
+			if overrunsDuration(lyrics, query.DurationMs) {
+				log.Printf("Lyrics: %s lyrics run past the track duration for %s - %s, likely a mismatch", provider.GetName(), artist, title)
+				if mismatch == nil {
+					mismatch = lyrics
+				}
+				continue
+			}
+			if isPlaceholderSource(lyrics.Source) && mismatch != nil {
+				break
+			}
 
-		if lyrics != nil && len(lyrics.Lines) > 0 {
 			// Cache the result (but skip caching demo/info fallback)
 			lyrics.TrackID = trackID
-			if !(strings.EqualFold(lyrics.Source, "Info") || strings.EqualFold(lyrics.Source, "Demo")) {
-				s.cache.SetByTrackID(trackID, lyrics)
+			lyrics.MatchConfidence = matchConfidence(lyrics, artist, title)
+			if !isPlaceholderSource(lyrics.Source) {
+				s.cacheByTrackID(trackID, lyrics)
 				s.cache.SetByKey(normalizedKey, lyrics)
+				if isrcKey != "" {
+					s.cache.SetByKey(isrcKey, lyrics)
+				}
 			} else {
 				log.Printf("Lyrics: not caching Info/Demo result for %s - %s", artist, title)
 			}
-			return lyrics, nil
+			return lyrics, lyricsResultError(lyrics)
+		}
+	}
+
+	if mismatch != nil {
+		// Better than nothing, but flagged and not cached so a later lookup can do better
+		mismatch.TrackID = trackID
+		mismatch.MatchConfidence = matchConfidence(mismatch, artist, title) * mismatchConfidencePenalty
+		return mismatch, lyricsResultError(mismatch)
+	}
+	if failed && !answered {
+		return nil, fmt.Errorf("%w for %s - %s", ErrProviderUnavailable, artist, title)
+	}
+	return nil, fmt.Errorf("%w for %s - %s", ErrNoLyrics, artist, title)
+}
+
+// cacheByTrackID caches lyrics under the track ID, skipping searches that have none
+func (s *Service) cacheByTrackID(trackID string, lyrics *overlay.LyricsData) {
+	if trackID != "" {
+		s.cache.SetByTrackID(trackID, lyrics)
+	}
+}
+
+// SetCountrySource registers a function reporting the user's country code, passed to
+// providers in TrackQuery.Country (nil removes it)
+func (s *Service) SetCountrySource(source func() string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.countrySource = source
+}
+
+// country returns the user's country code, or "" when unknown
+func (s *Service) country() string {
+	s.mu.RLock()
+	source := s.countrySource
+	s.mu.RUnlock()
+	if source == nil {
+		return ""
+	}
+	return source()
+}
+
+// LookupObserver is notified after each provider lookup (cache hits are not reported)
+type LookupObserver func(query TrackQuery, lyrics *overlay.LyricsData, err error)
+
+// SetLookupObserver registers a callback for provider lookups (nil removes it)
+func (s *Service) SetLookupObserver(observer LookupObserver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observer = observer
+}
+
+// notifyLookup reports a provider lookup to the observer, if any
+func (s *Service) notifyLookup(query TrackQuery, lyrics *overlay.LyricsData, err error) {
+	s.mu.RLock()
+	observer := s.observer
+	s.mu.RUnlock()
+	if observer != nil {
+		observer(query, lyrics, err)
+	}
+}
+
+// fromCache returns a copy of cached lyrics marked as served from the cache
+func fromCache(lyrics *overlay.LyricsData) *overlay.LyricsData {
+	cached := *lyrics
+	cached.FromCache = true
+	return &cached
+}
+
+// lyricsResultError returns ErrInstrumental for instrumental results so callers can branch on it
+func lyricsResultError(lyrics *overlay.LyricsData) error {
+	if lyrics.IsInstrumental {
+		return ErrInstrumental
+	}
+	return nil
+}
+
+const (
+	// overrunToleranceMs is how far synced lyrics may run past the track end before they're
+	// considered a mismatch, on top of overrunToleranceRatio of the duration
+	overrunToleranceMs    int64 = 30000
+	overrunToleranceRatio       = 0.25
+	// mismatchConfidencePenalty scales the confidence of lyrics kept despite overrunning
+	mismatchConfidencePenalty = 0.5
+)
+
+// overrunsDuration reports whether synced lyrics have timestamps far past the track's end,
+// which usually means they were matched to a different (e.g. extended) recording
+func overrunsDuration(lyrics *overlay.LyricsData, durationMs int64) bool {
+	if durationMs <= 0 || !lyrics.IsSynced || len(lyrics.Lines) == 0 {
+		return false
+	}
+	last := int64(0)
+	for _, line := range lyrics.Lines {
+		last = max(last, line.Timestamp)
+	}
+	limit := durationMs + max(overrunToleranceMs, int64(float64(durationMs)*overrunToleranceRatio))
+	return last > limit
+}
+
+// matchConfidence estimates (0-1) how likely the lyrics belong to the requested track,
+// from how closely the matched artist/title agree and whether synced lyrics were found
+func matchConfidence(lyrics *overlay.LyricsData, artist, title string) float64 {
+	if isPlaceholderSource(lyrics.Source) {
+		return 0
+	}
+
+	// Providers that don't report what they matched get a neutral similarity
+	artistScore, titleScore := 0.5, 0.5
+	if lyrics.MatchedArtist != "" {
+		artistScore = similarity(normalizeString(lyrics.MatchedArtist), normalizeString(artist))
+	}
+	if lyrics.MatchedTitle != "" {
+		titleScore = similarity(normalizeString(lyrics.MatchedTitle), normalizeString(title))
+	}
+
+	confidence := 0.4*artistScore + 0.4*titleScore
+	if lyrics.IsSynced || lyrics.IsInstrumental {
+		confidence += 0.2
+	}
+	return confidence
+}
+
+// similarity returns the word overlap (Jaccard index) of two normalized strings
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	aWords := strings.Fields(a)
+	bWords := strings.Fields(b)
+	if len(aWords) == 0 || len(bWords) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(aWords))
+	for _, w := range aWords {
+		set[w] = true
+	}
+	shared := 0
+	union := len(set)
+	seen := make(map[string]bool, len(bWords))
+	for _, w := range bWords {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if set[w] {
+			shared++
+		} else {
+			union++
 		}
 	}
+	return float64(shared) / float64(union)
+}
 
-	return nil, fmt.Errorf("no lyrics found for %s - %s", artist, title)
+// isPlaceholderSource reports whether a source is the track-info fallback rather than real lyrics
+func isPlaceholderSource(source string) bool {
+	return strings.EqualFold(source, "Info") || strings.EqualFold(source, "Demo")
+}
+
+// autoArtistSuffixes match markers added to auto-generated (e.g. YouTube import) artist names
+var autoArtistSuffixes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\s+-\s+topic$`),    // "Artist - Topic"
+	regexp.MustCompile(`(?i)\s+-\s+official$`), // "Artist - Official"
+	regexp.MustCompile(`(?i)\s*\(official\)$`), // "Artist (Official)"
+	regexp.MustCompile(`(\p{L})VEVO$`),         // "ArtistVEVO"
+}
+
+// cleanArtist strips auto-generated suffixes like " - Topic" from an artist name; names that
+// are only the marker (e.g. the band "Topic") are left intact
+func cleanArtist(artist string) string {
+	cleaned := strings.TrimSpace(artist)
+	for _, re := range autoArtistSuffixes {
+		cleaned = strings.TrimSpace(re.ReplaceAllString(cleaned, "$1"))
+	}
+	if cleaned == "" {
+		return artist
+	}
+	return cleaned
 }
 
 // normalizeForCache creates a normalized cache key from artist and title
@@ -111,10 +633,47 @@ func normalizeForCache(artist, title string) string {
 	return fmt.Sprintf("%s|%s", normalizedArtist, normalizedTitle)
 }
 
+// foldDiacritics controls whether accents on Latin letters are stripped during normalization
+// (e.g. "é" -> "e"); marks on other scripts, like the kana dakuten, change the word and are kept
+var foldDiacritics atomic.Bool
+
+func init() {
+	foldDiacritics.Store(true)
+}
+
+// SetDiacriticFolding enables or disables accent folding when normalizing titles and artists
+func SetDiacriticFolding(enabled bool) {
+	foldDiacritics.Store(enabled)
+}
+
+// normalizeUnicode applies NFKC normalization so composed and decomposed accents (and
+// compatibility forms like full-width letters) compare equal, optionally folding diacritics
+func normalizeUnicode(text string) string {
+	if foldDiacritics.Load() {
+		// Decompose, drop combining marks on Latin letters, then recompose what remains
+		decomposed := norm.NFKD.String(text)
+		var b strings.Builder
+		b.Grow(len(decomposed))
+		latinBase := false
+		for _, r := range decomposed {
+			if unicode.Is(unicode.Mn, r) {
+				if latinBase {
+					continue
+				}
+			} else {
+				latinBase = unicode.Is(unicode.Latin, r)
+			}
+			b.WriteRune(r)
+		}
+		text = b.String()
+	}
+	return norm.NFKC.String(text)
+}
+
 // normalizeString normalizes text for lyrics matching
 func normalizeString(text string) string {
-	// Convert to lowercase
-	text = strings.ToLower(text)
+	// Unify Unicode forms, then convert to lowercase
+	text = strings.ToLower(normalizeUnicode(text))
 
 	// Remove common patterns
 	patterns := []string{
@@ -137,8 +696,8 @@ func normalizeString(text string) string {
 		text = re.ReplaceAllString(text, "")
 	}
 
-	// Remove extra whitespace and special characters
-	re := regexp.MustCompile(`[^\w\s]`)
+	// Remove extra whitespace and special characters (letters in any script are kept)
+	re := regexp.MustCompile(`[^\p{L}\p{N}\s_]`)
 	text = re.ReplaceAllString(text, "")
 
 	// Normalize whitespace
@@ -148,6 +707,9 @@ func normalizeString(text string) string {
 	return strings.TrimSpace(text)
 }
 
+// sectionHeaderPattern matches structure markers such as "[Chorus]" or "[Verse 1: Artist]"
+var sectionHeaderPattern = regexp.MustCompile(`^\[[^\[\]]{1,60}\]$`)
+
 // textToLyricsLines converts raw lyrics text into overlay lines, filtering noise
 func textToLyricsLines(text string) []overlay.LyricsLine {
 	// Split lines, trim, and filter common non-lyrics artifacts
@@ -225,7 +787,7 @@ func textToLyricsLines(text string) []overlay.LyricsLine {
 			lastWasEmpty = true
 			continue
 		}
-		lines = append(lines, overlay.LyricsLine{Text: t})
+		lines = append(lines, overlay.LyricsLine{Text: t, IsSection: sectionHeaderPattern.MatchString(t)})
 		lastWasEmpty = false
 	}
 
@@ -242,16 +804,45 @@ func textToLyricsLines(text string) []overlay.LyricsLine {
 
 // LRCLibProvider implements lyrics fetching from LRCLIB
 type LRCLibProvider struct {
-	client  *http.Client
-	baseURL string
+	client      *http.Client
+	baseURL     string
+	preferPlain atomic.Bool // Use plain lyrics over synced ones for low-confidence matches
 }
 
-// NewLRCLibProvider creates a new LRCLIB provider
-func NewLRCLibProvider(client *http.Client) *LRCLibProvider {
+// DefaultLRCLibBaseURL is the public LRCLIB API root
+const DefaultLRCLibBaseURL = "https://lrclib.net/api"
+
+// lrclibPageURL is the lrclib.net page showing a record, by ID
+const lrclibPageURL = "https://lrclib.net/lyrics/%d"
+
+// NewLRCLibProvider creates a new LRCLIB provider querying the API at baseURL
+// (DefaultLRCLibBaseURL if empty)
+func NewLRCLibProvider(client *http.Client, baseURL string) *LRCLibProvider {
+	if baseURL == "" {
+		baseURL = DefaultLRCLibBaseURL
+	}
 	return &LRCLibProvider{
 		client:  client,
-		baseURL: "https://lrclib.net/api",
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// ValidateBaseURL checks that rawURL is an absolute http(s) URL usable as a provider API root
+func ValidateBaseURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid base URL %q: scheme must be http or https", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid base URL %q: missing host", rawURL)
 	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("invalid base URL %q: query and fragment are not allowed", rawURL)
+	}
+	return nil
 }
 
 // GetName returns the provider name
@@ -268,68 +859,121 @@ type lrcLibTrack struct {
 	Duration     float64 `json:"duration"` // seconds
 	PlainLyrics  string  `json:"plainLyrics"`
 	SyncedLyrics string  `json:"syncedLyrics"`
+	Instrumental bool    `json:"instrumental"`
+}
+
+// lrclibTimingDurationTolerance is how far (seconds) a match's duration may differ from the
+// Spotify track before its synced timings are considered unreliable
+const lrclibTimingDurationTolerance = 5.0
+
+// SetPreferPlainOnLowConfidence makes fuzzy matches, and matches whose duration differs from
+// the track, use plain lyrics when available since their synced timing is likely off
+func (l *LRCLibProvider) SetPreferPlainOnLowConfidence(enabled bool) {
+	l.preferPlain.Store(enabled)
+}
+
+// unreliableTiming reports whether a match's synced timings are likely off for the track
+func unreliableTiming(kind string, track *lrcLibTrack, durationMs int64) bool {
+	if kind != overlay.MatchExact {
+		return true
+	}
+	return durationMs > 0 && track.Duration > 0 && math.Abs(track.Duration-float64(durationMs)/1000) > lrclibTimingDurationTolerance
+}
+
+// preferPlainFor reports whether to use plain lyrics for a match of the given kind
+func (l *LRCLibProvider) preferPlainFor(kind string, track *lrcLibTrack, durationMs int64) bool {
+	return l.preferPlain.Load() && unreliableTiming(kind, track, durationMs)
 }
 
+// lrclibISRCDurationTolerance is how far (seconds) a candidate's duration may differ from the
+// Spotify track when the ISRC tells us we know the exact recording
+const lrclibISRCDurationTolerance = 2.0
+
 // SearchLyrics queries LRCLIB for lyrics
-func (l *LRCLibProvider) SearchLyrics(artist, title string) (*overlay.LyricsData, error) {
+func (l *LRCLibProvider) SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error) {
+	return l.SearchTrack(ctx, TrackQuery{Artist: artist, Title: title})
+}
+
+// SearchTrack queries LRCLIB for lyrics. LRCLIB has no ISRC lookup, so when the ISRC is known
+// the track duration is used to reject candidates from other recordings.
+func (l *LRCLibProvider) SearchTrack(ctx context.Context, query TrackQuery) (*overlay.LyricsData, error) {
+	artist, title := query.Artist, query.Title
+	durationSec := 0.0
+	if query.ISRC != "" && query.DurationMs > 0 {
+		durationSec = float64(query.DurationMs) / 1000
+	}
+
 	// First, try direct get endpoint for an exact match
-	if track := l.tryGet(artist, title); track != nil {
-		if data := l.trackToLyricsData(track); data != nil {
+	if track := l.tryGet(ctx, artist, title, durationSec); track != nil {
+		if data := l.trackToLyricsData(track, l.preferPlainFor(overlay.MatchExact, track, query.DurationMs)); data != nil {
+			data.MatchKind = overlay.MatchExact
+			data.MatchScore = scoreLRCLibMatch(track, normalizeString(artist), normalizeString(title))
 			return data, nil
 		}
 	}
 
 	// Fallback to search endpoint
-	results, err := l.search(artist, title)
+	results, err := l.search(ctx, artist, title)
 	if err != nil {
 		return nil, err
 	}
 
-	// If empty, try query fallback
+	// If empty, try progressively looser free-text queries
+	kind := overlay.MatchFuzzy
 	if len(results) == 0 {
-		q := strings.TrimSpace(fmt.Sprintf("%s %s", title, artist))
-		if q != "" {
-			results, err = l.searchByQuery(q)
+		kind = overlay.MatchFallback
+		for _, q := range loosenedQueries(artist, title) {
+			results, err = l.searchByQuery(ctx, q)
 			if err != nil {
 				return nil, err
 			}
+			if len(results) > 0 {
+				break
+			}
 		}
 		if len(results) == 0 {
-			return nil, fmt.Errorf("no lrclib results")
+			return nil, fmt.Errorf("lrclib: %w", ErrNoLyrics)
 		}
 	}
 
 	// Score and pick best match
-	best := pickBestLRCLibMatch(results, artist, title)
+	if durationSec > 0 {
+		results = filterByDuration(results, durationSec)
+	}
+	best, score := pickBestLRCLibMatch(results, artist, title)
 	if best == nil {
 		best = &results[0]
+		kind = overlay.MatchFallback
 	}
 
 	// Important: LRCLIB search results may not include lyrics; fetch by ID
-	full, err := l.getByID(best.ID)
+	full, err := l.getByID(ctx, best.ID)
 	if err == nil && full != nil {
-		if data := l.trackToLyricsData(full); data != nil {
+		if data := l.trackToLyricsData(full, l.preferPlainFor(kind, full, query.DurationMs)); data != nil {
+			data.MatchKind, data.MatchScore = kind, score
 			return data, nil
 		}
 	}
 
 	// Fallback to whatever search returned (if it had lyrics fields)
-	data := l.trackToLyricsData(best)
+	data := l.trackToLyricsData(best, l.preferPlainFor(kind, best, query.DurationMs))
 	if data == nil {
-		return nil, fmt.Errorf("lrclib returned empty lyrics")
+		return nil, fmt.Errorf("lrclib returned empty lyrics: %w", ErrNoLyrics)
 	}
+	data.MatchKind, data.MatchScore = kind, score
 	return data, nil
 }
 
-func (l *LRCLibProvider) tryGet(artist, title string) *lrcLibTrack {
+func (l *LRCLibProvider) tryGet(ctx context.Context, artist, title string, durationSec float64) *lrcLibTrack {
 	endpoint := fmt.Sprintf("%s/get?track_name=%s&artist_name=%s", l.baseURL, url.QueryEscape(title), url.QueryEscape(artist))
-	// Note: duration/album params can be added if available from caller
-	// e.g., &album_name=...&duration=...
-	req, err := http.NewRequest("GET", endpoint, nil)
+	// LRCLIB only returns a match within a couple of seconds of the given duration
+	if durationSec > 0 {
+		endpoint += fmt.Sprintf("&duration=%d", int(durationSec+0.5))
+	}
+	req, err := newProviderRequest(ctx, endpoint)
 	if err != nil {
 		return nil
 	}
-	req.Header.Set("Accept", "application/json")
 	resp, err := l.client.Do(req)
 	if err != nil {
 		return nil
@@ -338,28 +982,23 @@ func (l *LRCLibProvider) tryGet(artist, title string) *lrcLibTrack {
 	if resp.StatusCode != http.StatusOK {
 		return nil
 	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil
-	}
 	var track lrcLibTrack
-	if err := json.Unmarshal(body, &track); err != nil {
+	if err := decodeLRCLibJSON(resp, &track); err != nil {
 		return nil
 	}
-	if track.PlainLyrics == "" && track.SyncedLyrics == "" {
+	if track.PlainLyrics == "" && track.SyncedLyrics == "" && !track.Instrumental {
 		return nil
 	}
 	return &track
 }
 
-func (l *LRCLibProvider) search(artist, title string) ([]lrcLibTrack, error) {
+func (l *LRCLibProvider) search(ctx context.Context, artist, title string) ([]lrcLibTrack, error) {
 	endpoint := fmt.Sprintf("%s/search?track_name=%s&artist_name=%s", l.baseURL, url.QueryEscape(title), url.QueryEscape(artist))
 	// Note: duration/album params can be added if available from caller
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := newProviderRequest(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
 	resp, err := l.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -368,25 +1007,19 @@ func (l *LRCLibProvider) search(artist, title string) ([]lrcLibTrack, error) {
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("lrclib search status %d", resp.StatusCode)
 	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
 	var results []lrcLibTrack
-	if err := json.Unmarshal(body, &results); err != nil {
+	if err := decodeLRCLibJSON(resp, &results); err != nil {
 		return nil, err
 	}
 	return results, nil
 }
 
-func (l *LRCLibProvider) searchByQuery(query string) ([]lrcLibTrack, error) {
+func (l *LRCLibProvider) searchByQuery(ctx context.Context, query string) ([]lrcLibTrack, error) {
 	endpoint := fmt.Sprintf("%s/search?q=%s", l.baseURL, url.QueryEscape(query))
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := newProviderRequest(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "SpotLy/1.0")
 	resp, err := l.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -395,84 +1028,209 @@ func (l *LRCLibProvider) searchByQuery(query string) ([]lrcLibTrack, error) {
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("lrclib search status %d", resp.StatusCode)
 	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
 	var results []lrcLibTrack
-	if err := json.Unmarshal(body, &results); err != nil {
+	if err := decodeLRCLibJSON(resp, &results); err != nil {
 		return nil, err
 	}
 	return results, nil
 }
 
-func pickBestLRCLibMatch(results []lrcLibTrack, artist, title string) *lrcLibTrack {
-	nArtist := normalizeString(artist)
-	nTitle := normalizeString(title)
+// bracketedSuffix matches a trailing "(...)" or "[...]" group in a title
+var bracketedSuffix = regexp.MustCompile(`\s*[(\[][^()\[\]]*[)\]]\s*$`)
 
-	bestIdx := -1
-	bestScore := -1
-	for i, r := range results {
-		artistMatch := normalizeString(r.ArtistName) == nArtist
-		titleMatch := normalizeString(r.TrackName) == nTitle
-		score := 0
-		if artistMatch {
-			score += 3
+// looseTitle strips trailing bracketed groups and " - Remastered"-style version suffixes,
+// e.g. "Song (Bonus Track) - 2011 Remaster" becomes "Song"
+func looseTitle(title string) string {
+	loose := strings.TrimSpace(title)
+	for {
+		if left, right, found := strings.Cut(loose, " - "); found && titleVersionSuffixes.MatchString(strings.TrimSpace(right)) {
+			loose = strings.TrimSpace(left)
+			continue
 		}
-		if titleMatch {
-			score += 3
+		trimmed := strings.TrimSpace(bracketedSuffix.ReplaceAllString(loose, ""))
+		if trimmed == loose || trimmed == "" {
+			return loose
 		}
-		if r.SyncedLyrics != "" {
-			score += 2
+		loose = trimmed
+	}
+}
+
+// loosenedQueries returns the free-text queries to try when a structured search finds nothing,
+// loosest last: stripped title with artist, stripped title alone, then the title as given for
+// songs whose real name includes the brackets
+func loosenedQueries(artist, title string) []string {
+	loose := looseTitle(title)
+	candidates := []string{
+		strings.TrimSpace(loose + " " + strings.TrimSpace(artist)),
+		loose,
+		strings.TrimSpace(title),
+	}
+
+	queries := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, q := range candidates {
+		if q == "" || seen[q] {
+			continue
 		}
-		if r.PlainLyrics != "" {
-			score += 1
+		seen[q] = true
+		queries = append(queries, q)
+	}
+	return queries
+}
+
+// decodeLRCLibJSON decodes a successful LRCLIB response into v. During outages LRCLIB can answer
+// 200 with an HTML error page, so anything not labelled JSON is reported as unexpected rather
+// than surfacing a confusing parse error.
+func decodeLRCLibJSON(resp *http.Response, v interface{}) error {
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return fmt.Errorf("lrclib: provider returned unexpected response (%s)", contentType)
 		}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("lrclib: provider returned unexpected response: %w", err)
+	}
+	return nil
+}
+
+// filterByDuration keeps results close to the expected duration, or all of them if none are
+func filterByDuration(results []lrcLibTrack, durationSec float64) []lrcLibTrack {
+	filtered := make([]lrcLibTrack, 0, len(results))
+	for _, r := range results {
+		if r.Duration > 0 && math.Abs(r.Duration-durationSec) <= lrclibISRCDurationTolerance {
+			filtered = append(filtered, r)
+		}
+	}
+	if len(filtered) == 0 {
+		return results
+	}
+	return filtered
+}
+
+// pickBestLRCLibMatch returns the highest-scoring result and its score
+func pickBestLRCLibMatch(results []lrcLibTrack, artist, title string) (*lrcLibTrack, int) {
+	nArtist := normalizeString(artist)
+	nTitle := normalizeString(title)
+
+	bestIdx := -1
+	bestScore := -1
+	for i := range results {
+		score := scoreLRCLibMatch(&results[i], nArtist, nTitle)
 		if score > bestScore {
 			bestScore = score
 			bestIdx = i
 		}
 	}
 	if bestIdx >= 0 {
-		return &results[bestIdx]
+		return &results[bestIdx], bestScore
 	}
-	return nil
+	return nil, 0
+}
+
+// scoreLRCLibMatch ranks a result against the normalized artist and title
+func scoreLRCLibMatch(r *lrcLibTrack, nArtist, nTitle string) int {
+	score := 0
+	if normalizeString(r.ArtistName) == nArtist {
+		score += 3
+	}
+	if normalizeString(r.TrackName) == nTitle {
+		score += 3
+	}
+	if r.SyncedLyrics != "" {
+		score += 2
+	}
+	if r.PlainLyrics != "" {
+		score += 1
+	}
+	return score
+}
+
+func (l *LRCLibProvider) trackToLyricsData(track *lrcLibTrack, preferPlain bool) *overlay.LyricsData {
+	data := lrcLibLyrics(track, preferPlain)
+	if data == nil {
+		return nil
+	}
+	// Only the public instance has a known page for a record; self-hosted ones just serve the API
+	if track.ID > 0 && l.baseURL == DefaultLRCLibBaseURL {
+		data.SourceURL = fmt.Sprintf(lrclibPageURL, track.ID)
+	}
+	data.MatchedAlbum = track.AlbumName
+	data.MatchedDurationMs = int64(math.Round(track.Duration * 1000))
+	return data
 }
 
-func (l *LRCLibProvider) trackToLyricsData(track *lrcLibTrack) *overlay.LyricsData {
+// lrcLibLyrics converts an LRCLIB record into lyrics, preferring synced over plain lyrics
+// unless preferPlain is set and the record has both
+func lrcLibLyrics(track *lrcLibTrack, preferPlain bool) *overlay.LyricsData {
 	if track == nil {
 		return nil
 	}
+	// Instrumentals have no lyrics; report that rather than falling through to Demo
+	if track.Instrumental {
+		return &overlay.LyricsData{
+			Source:         "LRCLIB",
+			IsInstrumental: true,
+			FetchedAt:      time.Now(),
+			Lines:          []overlay.LyricsLine{},
+			MatchedArtist:  track.ArtistName,
+			MatchedTitle:   track.TrackName,
+		}
+	}
+	if preferPlain && track.PlainLyrics != "" {
+		if data := plainLRCLibLyrics(track); data != nil {
+			return data
+		}
+	}
 	if track.SyncedLyrics != "" {
 		lines := parseLRCToLines(track.SyncedLyrics)
 		if len(lines) > 0 {
 			return &overlay.LyricsData{
-				Source:    "LRCLIB",
-				IsSynced:  true,
-				FetchedAt: time.Now(),
-				Lines:     lines,
+				Source:        "LRCLIB",
+				IsSynced:      true,
+				FetchedAt:     time.Now(),
+				Lines:         lines,
+				MatchedArtist: track.ArtistName,
+				MatchedTitle:  track.TrackName,
 			}
 		}
 	}
-	if track.PlainLyrics != "" {
-		lines := textToLyricsLines(track.PlainLyrics)
-		if len(lines) > 0 {
-			return &overlay.LyricsData{
-				Source:    "LRCLIB",
-				IsSynced:  false,
-				FetchedAt: time.Now(),
-				Lines:     lines,
-			}
-		}
+	return plainLRCLibLyrics(track)
+}
+
+// plainLRCLibLyrics converts an LRCLIB record's plain lyrics, or returns nil if it has none
+func plainLRCLibLyrics(track *lrcLibTrack) *overlay.LyricsData {
+	if track.PlainLyrics == "" {
+		return nil
+	}
+	lines := textToLyricsLines(track.PlainLyrics)
+	if len(lines) == 0 {
+		return nil
+	}
+	return &overlay.LyricsData{
+		Source:        "LRCLIB",
+		IsSynced:      false,
+		FetchedAt:     time.Now(),
+		Lines:         lines,
+		MatchedArtist: track.ArtistName,
+		MatchedTitle:  track.TrackName,
 	}
-	return nil
 }
 
 // parseLRCToLines parses LRC formatted lyrics into timestamped lines
 func parseLRCToLines(lrc string) []overlay.LyricsLine {
 	lines := make([]overlay.LyricsLine, 0)
-	// Timestamp pattern: [mm:ss.xx] or [mm:ss.xxx]
-	re := regexp.MustCompile(`\[(\d{1,2}):(\d{1,2})(?:\.(\d{1,3}))?\]`)
+	// Exact (timestamp, text) pairs already added; bad merges can repeat them and stall the display
+	type lineKey struct {
+		timestamp int64
+		text      string
+	}
+	seen := make(map[lineKey]bool)
+	re := lrcTimestampPattern
 	for _, raw := range strings.Split(lrc, "\n") {
 		raw = strings.TrimSpace(raw)
 		if raw == "" {
@@ -486,37 +1244,113 @@ func parseLRCToLines(lrc string) []overlay.LyricsLine {
 		if len(matches) == 0 {
 			continue
 		}
-		// Extract text after last timestamp tag
+		// Extract text after last timestamp tag, splitting out enhanced LRC word timings
 		last := matches[len(matches)-1]
-		text := strings.TrimSpace(raw[last[1]:])
+		text, words := parseLRCWords(raw[last[1]:])
 		if text == "" {
 			continue
 		}
 		for _, m := range matches {
-			mm := raw[m[0]:m[1]]
-			parts := re.FindStringSubmatch(mm)
-			if len(parts) >= 3 {
-				min := atoiSafe(parts[1])
-				sec := atoiSafe(parts[2])
-				ms := 0
-				if len(parts) >= 4 && parts[3] != "" {
-					p := parts[3]
-					if len(p) == 2 { // .xx -> .xx0
-						p = p + "0"
-					}
-					if len(p) == 1 { // .x -> .x00
-						p = p + "00"
-					}
-					ms = atoiSafe(p)
+			parts := re.FindStringSubmatch(raw[m[0]:m[1]])
+			if len(parts) == 5 {
+				timestamp := lrcTimestampMs(parts[1], parts[2], parts[3], parts[4])
+				key := lineKey{timestamp, text}
+				if seen[key] {
+					continue
 				}
-				timestamp := int64(min*60*1000 + sec*1000 + ms)
-				lines = append(lines, overlay.LyricsLine{Text: text, Timestamp: timestamp})
+				seen[key] = true
+				lines = append(lines, overlay.LyricsLine{Text: text, Timestamp: timestamp, Words: words})
 			}
 		}
 	}
-	// Sort by timestamp
-	sort.Slice(lines, func(i, j int) bool { return lines[i].Timestamp < lines[j].Timestamp })
-	return lines
+	// Sort by timestamp, keeping file order for lines that share one
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Timestamp < lines[j].Timestamp })
+	return mergeBilingualLines(lines)
+}
+
+// mergeBilingualLines folds lines sharing a timestamp (original + translation) into a single
+// line with SecondaryText, so bilingual files don't render as flickering duplicates
+func mergeBilingualLines(lines []overlay.LyricsLine) []overlay.LyricsLine {
+	merged := lines[:0]
+	for _, line := range lines {
+		if n := len(merged); n > 0 && merged[n-1].Timestamp == line.Timestamp {
+			prev := &merged[n-1]
+			if prev.SecondaryText == "" {
+				prev.SecondaryText = line.Text
+			} else {
+				prev.SecondaryText += " / " + line.Text
+			}
+			continue
+		}
+		merged = append(merged, line)
+	}
+	return merged
+}
+
+// lrcTimestampPattern matches line timestamps: [mm:ss], [mm:ss.xx], [mm:ss.xxx], with any number
+// of minute digits (e.g. [100:05.00]) and an optional hours part (e.g. [1:02:03.45])
+var lrcTimestampPattern = regexp.MustCompile(`\[(?:(\d+):)?(\d+):(\d{1,2})(?:\.(\d{1,3}))?\]`)
+
+// wordTagPattern matches enhanced LRC word timestamps (<mm:ss.xx>) in the same forms
+var wordTagPattern = regexp.MustCompile(`<(?:(\d+):)?(\d+):(\d{1,2})(?:\.(\d{1,3}))?>`)
+
+// parseLRCWords strips enhanced LRC word tags from a line, returning the plain text and the
+// timed words (nil when the line has no word timings)
+func parseLRCWords(raw string) (string, []overlay.LyricsWord) {
+	matches := wordTagPattern.FindAllStringSubmatchIndex(raw, -1)
+	if len(matches) == 0 {
+		return strings.TrimSpace(raw), nil
+	}
+
+	words := make([]overlay.LyricsWord, 0, len(matches))
+	for i, m := range matches {
+		end := len(raw)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		// Optional groups (hours, fraction) report -1 indexes when absent
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return raw[m[2*i]:m[2*i+1]]
+		}
+		timestamp := lrcTimestampMs(group(1), group(2), group(3), group(4))
+		word := strings.TrimSpace(raw[m[1]:end])
+		if word == "" {
+			// Trailing tag marking the end of the last word
+			if len(words) > 0 && i == len(matches)-1 {
+				words[len(words)-1].End = timestamp
+			}
+			continue
+		}
+		words = append(words, overlay.LyricsWord{Text: word, Timestamp: timestamp})
+	}
+
+	text := strings.Join(strings.Fields(wordTagPattern.ReplaceAllString(raw, "")), " ")
+	if len(words) == 0 {
+		return text, nil
+	}
+	return text, words
+}
+
+// lrcTimestampMs converts optional hour, minute, second and optional fraction captures to milliseconds
+func lrcTimestampMs(hours, minutes, seconds, fraction string) int64 {
+	hour := atoiSafe(hours)
+	min := atoiSafe(minutes)
+	sec := atoiSafe(seconds)
+	ms := 0
+	if fraction != "" {
+		p := fraction
+		if len(p) == 2 { // .xx -> .xx0
+			p = p + "0"
+		}
+		if len(p) == 1 { // .x -> .x00
+			p = p + "00"
+		}
+		ms = atoiSafe(p)
+	}
+	return int64(hour*3600*1000 + min*60*1000 + sec*1000 + ms)
 }
 
 func atoiSafe(s string) int {
@@ -532,35 +1366,27 @@ func atoiSafe(s string) int {
 }
 
 // getByID fetches a single track with lyrics by LRCLIB ID
-func (l *LRCLibProvider) getByID(id int) (*lrcLibTrack, error) {
+func (l *LRCLibProvider) getByID(ctx context.Context, id int) (*lrcLibTrack, error) {
 	// Try REST style first: /get/{id}
 	endpoint := fmt.Sprintf("%s/get/%d", l.baseURL, id)
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := newProviderRequest(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "SpotLy/1.0")
 	resp, err := l.client.Do(req)
 	if err == nil && resp != nil && resp.StatusCode == http.StatusOK {
 		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
-		}
 		var track lrcLibTrack
-		if err := json.Unmarshal(body, &track); err == nil {
+		if err := decodeLRCLibJSON(resp, &track); err == nil {
 			return &track, nil
 		}
 	}
 	// Fallback to query param style: /get?id=123
 	endpoint = fmt.Sprintf("%s/get?id=%d", l.baseURL, id)
-	req, err = http.NewRequest("GET", endpoint, nil)
+	req, err = newProviderRequest(ctx, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "SpotLy/1.0")
 	resp, err = l.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -569,12 +1395,8 @@ func (l *LRCLibProvider) getByID(id int) (*lrcLibTrack, error) {
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("lrclib get status %d", resp.StatusCode)
 	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
 	var track lrcLibTrack
-	if err := json.Unmarshal(body, &track); err != nil {
+	if err := decodeLRCLibJSON(resp, &track); err != nil {
 		return nil, err
 	}
 	return &track, nil
@@ -594,7 +1416,7 @@ func (d *DemoProvider) GetName() string {
 }
 
 // SearchLyrics provides fallback when no other provider works
-func (d *DemoProvider) SearchLyrics(artist, title string) (*overlay.LyricsData, error) {
+func (d *DemoProvider) SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error) {
 	// Only provide basic track info, not full lyrics
 	lyrics := &overlay.LyricsData{
 		Source:    "Info",
@@ -611,6 +1433,33 @@ func (d *DemoProvider) SearchLyrics(artist, title string) (*overlay.LyricsData,
 	return lyrics, nil
 }
 
+// SyncedDemo returns sample synced lyrics with real timestamps, for previewing the overlay
+// without music
+func (d *DemoProvider) SyncedDemo() *overlay.LyricsData {
+	return &overlay.LyricsData{
+		TrackID:   DemoTrackID,
+		Source:    "Demo",
+		IsSynced:  true,
+		FetchedAt: time.Now(),
+		Lines: parseLRCToLines(`[00:00.50]🎵 SpotLy demo playback
+[00:03.00]Lyrics move in time with the music
+[00:06.00]Adjust the font size and opacity
+[00:09.00]And watch each line as it changes
+[00:12.00]
+[00:14.00]Short line
+[00:16.00]A much longer line to check how wrapping looks across the overlay width
+[00:21.00]Try the sync offset to shift timing
+[00:24.50]Word <00:24.50>by <00:25.00>word <00:25.50>timing <00:26.00>works <00:26.50>too
+[00:28.00]♪ Back to the top ♪`),
+	}
+}
+
+// DemoTrackID is the track ID used for demo playback
+const DemoTrackID = "spotly-demo"
+
+// DemoDurationMs is the length of the demo playback loop
+const DemoDurationMs int64 = 31000
+
 // ParseSyncedLyrics parses LRC formatted synced lyrics into timestamped lines.
 // This is a public wrapper for testing purposes.
 func ParseSyncedLyrics(lrc string) []overlay.LyricsLine {
@@ -622,3 +1471,9 @@ func ParseSyncedLyrics(lrc string) []overlay.LyricsLine {
 func NormalizeTitle(title string) string {
 	return normalizeString(title)
 }
+
+// NormalizeCacheKey returns the normalized "artist|title" key lookups cache lyrics under,
+// including the artist cleanup applied before lookup. Tracks sharing a key share lyrics.
+func NormalizeCacheKey(artist, title string) string {
+	return normalizeForCache(cleanArtist(artist), title)
+}
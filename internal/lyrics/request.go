@@ -0,0 +1,44 @@
+package lyrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// appVersion is reported to lyrics providers; keep in sync with wails.json productVersion
+const appVersion = "1.0.0"
+
+// userAgent is sent with every provider request
+var userAgent atomic.Value
+
+func init() {
+	SetContact("")
+}
+
+// SetContact sets optional contact details (e.g. an email) included in the User-Agent so
+// provider operators can reach the user instead of blocking the app
+func SetContact(contact string) {
+	agent := "SpotLy/" + appVersion
+	if contact = strings.TrimSpace(contact); contact != "" {
+		agent += " (" + contact + ")"
+	}
+	userAgent.Store(agent)
+}
+
+// UserAgent returns the User-Agent sent to lyrics providers
+func UserAgent() string {
+	return userAgent.Load().(string)
+}
+
+// newProviderRequest builds a GET request to a lyrics provider with the shared headers
+func newProviderRequest(ctx context.Context, endpoint string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
@@ -0,0 +1,747 @@
+package lyrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"lyrics-overlay/internal/cache"
+	"lyrics-overlay/internal/overlay"
+)
+
+// mockProvider is a LyricsProvider returning a fixed result
+type mockProvider struct {
+	name   string
+	result *overlay.LyricsData
+	err    error
+	calls  int
+}
+
+func (m *mockProvider) GetName() string {
+	return m.name
+}
+
+func (m *mockProvider) SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.result == nil {
+		return nil, nil
+	}
+	// Return a copy so the service can set TrackID without sharing state between calls
+	data := *m.result
+	return &data, nil
+}
+
+func TestGetLyrics_CachesSyncedResult(t *testing.T) {
+	c := cache.New(10)
+	provider := &mockProvider{
+		name: "Mock",
+		result: &overlay.LyricsData{
+			Source:   "Mock",
+			IsSynced: true,
+			Lines:    []overlay.LyricsLine{{Text: "line", Timestamp: 1000}},
+		},
+	}
+	s := NewWithProviders(c, provider)
+
+	lyrics, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title")
+	if err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if lyrics.TrackID != "track1" {
+		t.Errorf("TrackID = %q; want %q", lyrics.TrackID, "track1")
+	}
+
+	if got := c.GetByTrackID("track1"); got == nil {
+		t.Error("Expected result to be cached by track ID")
+	}
+	if got := c.GetByKey(normalizeForCache("Artist", "Title")); got == nil {
+		t.Error("Expected result to be cached by normalized key")
+	}
+
+	if lyrics.FromCache {
+		t.Error("Freshly fetched lyrics should not be marked as cached")
+	}
+
+	// Second lookup should be served from cache
+	cached, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title")
+	if err != nil {
+		t.Fatalf("GetLyrics (cached) failed: %v", err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("Provider called %d times; want 1", provider.calls)
+	}
+	if !cached.FromCache || cached.Source != "Mock" {
+		t.Errorf("Cached result = source %q, from cache %v; want Mock, true", cached.Source, cached.FromCache)
+	}
+	if c.GetByTrackID("track1").FromCache {
+		t.Error("Marking a cache hit should not modify the cached entry")
+	}
+}
+
+func TestGetLyrics_DoesNotCacheInfo(t *testing.T) {
+	c := cache.New(10)
+	provider := &mockProvider{
+		name: "Demo",
+		result: &overlay.LyricsData{
+			Source: "Info",
+			Lines:  []overlay.LyricsLine{{Text: "🎵 Title"}},
+		},
+	}
+	s := NewWithProviders(c, provider)
+
+	lyrics, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title")
+	if err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if lyrics.Source != "Info" {
+		t.Errorf("Source = %q; want %q", lyrics.Source, "Info")
+	}
+	if c.Size() != 0 {
+		t.Errorf("Cache size = %d; want 0", c.Size())
+	}
+}
+
+func TestGetLyrics_ErroringProviderFallsThrough(t *testing.T) {
+	failing := &mockProvider{name: "Failing", err: errors.New("boom")}
+	empty := &mockProvider{name: "Empty"}
+	working := &mockProvider{
+		name: "Working",
+		result: &overlay.LyricsData{
+			Source: "Working",
+			Lines:  []overlay.LyricsLine{{Text: "hello"}},
+		},
+	}
+	s := NewWithProviders(cache.New(10), failing, empty, working)
+
+	lyrics, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title")
+	if err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if lyrics.Source != "Working" {
+		t.Errorf("Source = %q; want %q", lyrics.Source, "Working")
+	}
+	if failing.calls != 1 || empty.calls != 1 || working.calls != 1 {
+		t.Errorf("Provider calls = %d/%d/%d; want 1/1/1", failing.calls, empty.calls, working.calls)
+	}
+}
+
+func TestGetLyrics_NoProviderResult(t *testing.T) {
+	s := NewWithProviders(cache.New(10), &mockProvider{name: "Failing", err: errors.New("boom")})
+
+	if _, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title"); err == nil {
+		t.Error("Expected error when no provider returns lyrics")
+	}
+}
+
+func TestGetLyrics_ErrorKinds(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []LyricsProvider
+		want      error
+	}{
+		{"all providers down", []LyricsProvider{&mockProvider{name: "Down", err: errors.New("dial tcp: timeout")}}, ErrProviderUnavailable},
+		{"provider has no match", []LyricsProvider{&mockProvider{name: "Empty"}}, ErrNoLyrics},
+		{"not found wins over outage", []LyricsProvider{
+			&mockProvider{name: "Down", err: errors.New("connection refused")},
+			&mockProvider{name: "Missing", err: fmt.Errorf("missing: %w", ErrNoLyrics)},
+		}, ErrNoLyrics},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewWithProviders(cache.New(10), tc.providers...)
+			_, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title")
+			if !errors.Is(err, tc.want) {
+				t.Errorf("GetLyrics error = %v; want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetLyrics_InstrumentalStopsChain(t *testing.T) {
+	instrumental := &mockProvider{
+		name:   "LRCLIB",
+		result: &overlay.LyricsData{Source: "LRCLIB", IsInstrumental: true},
+	}
+	demo := &mockProvider{
+		name:   "Demo",
+		result: &overlay.LyricsData{Source: "Info", Lines: []overlay.LyricsLine{{Text: "🎵 Title"}}},
+	}
+	s := NewWithProviders(cache.New(10), instrumental, demo)
+
+	lyrics, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title")
+	if !errors.Is(err, ErrInstrumental) {
+		t.Fatalf("GetLyrics error = %v; want ErrInstrumental", err)
+	}
+	if lyrics == nil || !lyrics.IsInstrumental {
+		t.Error("Expected instrumental result")
+	}
+	if demo.calls != 0 {
+		t.Errorf("Demo provider called %d times; want 0", demo.calls)
+	}
+}
+
+// slowProvider blocks until its context is done
+type slowProvider struct{}
+
+func (p *slowProvider) GetName() string {
+	return "Slow"
+}
+
+func (p *slowProvider) SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestGetLyrics_SlowProviderHonorsDeadline(t *testing.T) {
+	working := &mockProvider{
+		name:   "Working",
+		result: &overlay.LyricsData{Source: "Working", Lines: []overlay.LyricsLine{{Text: "hello"}}},
+	}
+	s := NewWithProviders(cache.New(10), &slowProvider{}, working)
+	s.SetProviderTimeout("Slow", 50*time.Millisecond)
+
+	start := time.Now()
+	lyrics, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title")
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if lyrics.Source != "Working" {
+		t.Errorf("Source = %q; want %q", lyrics.Source, "Working")
+	}
+	if elapsed > time.Second {
+		t.Errorf("Slow provider blocked for %v; want close to its 50ms deadline", elapsed)
+	}
+}
+
+func TestGetLyrics_TotalDeadline(t *testing.T) {
+	working := &mockProvider{
+		name:   "Working",
+		result: &overlay.LyricsData{Source: "Working", Lines: []overlay.LyricsLine{{Text: "hello"}}},
+	}
+	s := NewWithProviders(cache.New(10), &slowProvider{}, working)
+	s.SetTotalTimeout(50 * time.Millisecond)
+
+	if _, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title"); err == nil {
+		t.Error("Expected error once the total deadline is exhausted")
+	}
+	if working.calls != 0 {
+		t.Errorf("Provider after deadline called %d times; want 0", working.calls)
+	}
+}
+
+func TestMatchConfidence(t *testing.T) {
+	tests := []struct {
+		name   string
+		lyrics *overlay.LyricsData
+		min    float64
+		max    float64
+	}{
+		{
+			"exact synced match",
+			&overlay.LyricsData{Source: "LRCLIB", IsSynced: true, MatchedArtist: "Artist", MatchedTitle: "Song - Remaster"},
+			1, 1,
+		},
+		{
+			"exact plain match",
+			&overlay.LyricsData{Source: "LRCLIB", MatchedArtist: "Artist", MatchedTitle: "Song"},
+			0.8, 0.8,
+		},
+		{
+			"wrong song",
+			&overlay.LyricsData{Source: "LRCLIB", IsSynced: true, MatchedArtist: "Other Band", MatchedTitle: "Different Tune"},
+			0.2, 0.2,
+		},
+		{
+			"partial title",
+			&overlay.LyricsData{Source: "LRCLIB", IsSynced: true, MatchedArtist: "Artist", MatchedTitle: "Song Part Two"},
+			0.7, 0.8,
+		},
+		{
+			"placeholder",
+			&overlay.LyricsData{Source: "Info"},
+			0, 0,
+		},
+	}
+
+	for _, tc := range tests {
+		got := matchConfidence(tc.lyrics, "Artist", "Song")
+		if got < tc.min-1e-9 || got > tc.max+1e-9 {
+			t.Errorf("%s: matchConfidence = %.3f; want [%.2f, %.2f]", tc.name, got, tc.min, tc.max)
+		}
+	}
+}
+
+// trackMockProvider records the query it receives through the TrackProvider interface
+type trackMockProvider struct {
+	mockProvider
+	query TrackQuery
+}
+
+func (m *trackMockProvider) SearchTrack(ctx context.Context, query TrackQuery) (*overlay.LyricsData, error) {
+	m.query = query
+	return m.SearchLyrics(ctx, query.Artist, query.Title)
+}
+
+func TestGetLyricsForTrack_UsesISRC(t *testing.T) {
+	c := cache.New(10)
+	provider := &trackMockProvider{mockProvider: mockProvider{
+		name:   "Mock",
+		result: &overlay.LyricsData{Source: "Mock", IsSynced: true, Lines: []overlay.LyricsLine{{Text: "line"}}},
+	}}
+	s := NewWithProviders(c, provider)
+
+	track := &overlay.TrackInfo{ID: "track1", Name: "Title", Artists: []string{"Artist"}, Duration: 180000, ISRC: "usabc1234567"}
+	if _, err := s.GetLyricsForTrack(context.Background(), track); err != nil {
+		t.Fatalf("GetLyricsForTrack failed: %v", err)
+	}
+	if provider.query.ISRC != "usabc1234567" || provider.query.DurationMs != 180000 {
+		t.Errorf("Provider query = %+v; want ISRC and duration passed through", provider.query)
+	}
+
+	// A different Spotify ID and title for the same recording resolves from the ISRC cache entry
+	relinked := &overlay.TrackInfo{ID: "track2", Name: "Title (Live)", Artists: []string{"Other"}, ISRC: "USABC1234567"}
+	if _, err := s.GetLyricsForTrack(context.Background(), relinked); err != nil {
+		t.Fatalf("GetLyricsForTrack (relinked) failed: %v", err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("Provider calls = %d; want 1 (ISRC cache hit)", provider.calls)
+	}
+}
+
+func TestGetLyrics_PassesCountry(t *testing.T) {
+	provider := &trackMockProvider{mockProvider: mockProvider{
+		name:   "Mock",
+		result: &overlay.LyricsData{Source: "Mock", Lines: []overlay.LyricsLine{{Text: "line"}}},
+	}}
+	s := NewWithProviders(cache.New(10), provider)
+
+	if _, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title"); err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if provider.query.Country != "" {
+		t.Errorf("Country = %q without a source; want empty", provider.query.Country)
+	}
+
+	s.SetCountrySource(func() string { return "SE" })
+	if _, err := s.GetLyrics(context.Background(), "track2", "Artist", "Other"); err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if provider.query.Country != "SE" {
+		t.Errorf("Country = %q; want SE", provider.query.Country)
+	}
+}
+
+func TestGetLyrics_DemotesFailingProvider(t *testing.T) {
+	broken := &mockProvider{name: "Broken", err: errors.New("401 unauthorized")}
+	working := &mockProvider{name: "Working", result: &overlay.LyricsData{
+		Source: "Working",
+		Lines:  []overlay.LyricsLine{{Text: "line"}},
+	}}
+	s := NewWithProviders(cache.New(10), broken, working)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	// Distinct tracks so the cache doesn't short-circuit the chain
+	for i := 0; i < providerFailureThreshold+2; i++ {
+		id := fmt.Sprintf("track%d", i)
+		if _, err := s.GetLyrics(context.Background(), id, "Artist", id); err != nil {
+			t.Fatalf("GetLyrics failed: %v", err)
+		}
+	}
+	if broken.calls != providerFailureThreshold {
+		t.Errorf("Broken provider calls = %d; want %d before demotion", broken.calls, providerFailureThreshold)
+	}
+
+	health := s.ProviderHealth()
+	if len(health) != 2 || health[0].Healthy || !health[1].Healthy {
+		t.Fatalf("ProviderHealth = %+v; want Broken demoted and Working healthy", health)
+	}
+	if health[0].LastError == "" {
+		t.Error("Expected the demoted provider's last error to be reported")
+	}
+
+	// After the cooldown the provider is retried
+	now = now.Add(providerCooldown + time.Second)
+	if _, err := s.GetLyrics(context.Background(), "later", "Artist", "later"); err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if broken.calls != providerFailureThreshold+1 {
+		t.Errorf("Broken provider calls = %d; want a retry after cooldown", broken.calls)
+	}
+}
+
+func TestRecordProviderResult_NoLyricsIsNotAFailure(t *testing.T) {
+	s := NewWithProviders(cache.New(10), &mockProvider{name: "Mock"})
+	for i := 0; i < providerFailureThreshold+1; i++ {
+		s.recordProviderResult("Mock", ErrNoLyrics)
+	}
+	if s.providerDemoted("Mock") {
+		t.Error("Providers answering \"no lyrics\" should not be demoted")
+	}
+}
+
+func TestLoadDataset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dataset.json")
+	dataset := `[
+		{"artist": "Artist - Topic", "title": "Song - Remaster", "lyrics": {"source": "LRCLIB", "is_synced": true, "lines": [{"text": "offline line", "timestamp": 1000}]}},
+		{"artist": "Nobody", "title": "Empty", "lyrics": {"lines": []}}
+	]`
+	if err := os.WriteFile(path, []byte(dataset), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	failing := &mockProvider{name: "Offline", err: errors.New("network down")}
+	s := NewWithProviders(cache.New(10), failing)
+	loaded, err := s.LoadDataset(path)
+	if err != nil {
+		t.Fatalf("LoadDataset failed: %v", err)
+	}
+	if loaded != 1 {
+		t.Errorf("Loaded = %d; want 1 (empty entries skipped)", loaded)
+	}
+
+	lyrics, err := s.GetLyrics(context.Background(), "track1", "Artist", "Song")
+	if err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if lyrics.Source != DatasetSource || lyrics.Lines[0].Text != "offline line" {
+		t.Errorf("GetLyrics = %+v; want the dataset lyrics", lyrics)
+	}
+	if failing.calls != 0 {
+		t.Error("Expected dataset lyrics to be served without querying providers")
+	}
+}
+
+func TestSearchLyrics_CachesByKeyOnly(t *testing.T) {
+	c := cache.New(10)
+	provider := &mockProvider{name: "Mock", result: &overlay.LyricsData{
+		Source: "Mock",
+		Lines:  []overlay.LyricsLine{{Text: "line"}},
+	}}
+	s := NewWithProviders(c, provider)
+
+	lyrics, err := s.SearchLyrics(context.Background(), "Artist", "Title")
+	if err != nil {
+		t.Fatalf("SearchLyrics failed: %v", err)
+	}
+	if lyrics.Lines[0].Text != "line" {
+		t.Errorf("SearchLyrics = %+v; want the provider lyrics", lyrics)
+	}
+	if stats := c.Stats(); stats.TrackEntries != 0 || stats.KeyEntries != 1 {
+		t.Errorf("Cache stats = %+v; want one key entry and no track entries", stats)
+	}
+
+	// A second search is served from the cache
+	if _, err := s.SearchLyrics(context.Background(), "Artist", "Title"); err != nil {
+		t.Fatalf("SearchLyrics failed: %v", err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("Provider calls = %d; want 1", provider.calls)
+	}
+}
+
+func TestSplitArtistFromTitle(t *testing.T) {
+	tests := []struct {
+		artist, title         string
+		wantArtist, wantTitle string
+		wantOK                bool
+	}{
+		{"", "Daft Punk - One More Time", "Daft Punk", "One More Time", true},
+		{"Various Artists", "Queen - Bohemian Rhapsody - Remastered 2011", "Queen", "Bohemian Rhapsody - Remastered 2011", true},
+		{"Unknown Artist", "Song - Live", "", "", false}, // Version marker, not an artist
+		{"Unknown Artist", "Song - 2011 Remaster", "", "", false},
+		{"Real Artist", "Song - With Hyphen", "", "", false}, // Artist is known
+		{"", "Self-Titled", "", "", false},                   // No spaced hyphen
+	}
+
+	for _, tc := range tests {
+		artist, title, ok := splitArtistFromTitle(tc.artist, tc.title)
+		if ok != tc.wantOK || artist != tc.wantArtist || title != tc.wantTitle {
+			t.Errorf("splitArtistFromTitle(%q, %q) = %q, %q, %v; want %q, %q, %v",
+				tc.artist, tc.title, artist, title, ok, tc.wantArtist, tc.wantTitle, tc.wantOK)
+		}
+	}
+}
+
+// splitMockProvider only knows lyrics for one artist/title pair
+type splitMockProvider struct {
+	artist, title string
+	queries       []string
+}
+
+func (m *splitMockProvider) GetName() string {
+	return "SplitMock"
+}
+
+func (m *splitMockProvider) SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error) {
+	m.queries = append(m.queries, artist+"|"+title)
+	if artist == m.artist && title == m.title {
+		return &overlay.LyricsData{Source: "SplitMock", Lines: []overlay.LyricsLine{{Text: "found"}}}, nil
+	}
+	return nil, ErrNoLyrics
+}
+
+func TestGetLyrics_SplitsArtistFromTitle(t *testing.T) {
+	provider := &splitMockProvider{artist: "Daft Punk", title: "One More Time"}
+	s := NewWithProviders(cache.New(10), provider)
+
+	lyrics, err := s.GetLyrics(context.Background(), "track1", "Unknown Artist", "Daft Punk - One More Time")
+	if err != nil {
+		t.Fatalf("GetLyrics failed: %v", err)
+	}
+	if lyrics.Lines[0].Text != "found" {
+		t.Errorf("GetLyrics = %+v; want lyrics found after splitting", lyrics)
+	}
+
+	// A real artist with a hyphenated title is not split
+	provider.queries = nil
+	if _, err := s.GetLyrics(context.Background(), "track2", "Band", "Song - Part Two"); !errors.Is(err, ErrNoLyrics) {
+		t.Errorf("GetLyrics error = %v; want ErrNoLyrics", err)
+	}
+	if len(provider.queries) != 1 {
+		t.Errorf("Queries = %v; want a single unsplit lookup", provider.queries)
+	}
+}
+
+// concurrencyProvider records the peak number of simultaneous lookups
+type concurrencyProvider struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (p *concurrencyProvider) GetName() string {
+	return "Concurrency"
+}
+
+func (p *concurrencyProvider) SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error) {
+	p.mu.Lock()
+	p.inFlight++
+	if p.inFlight > p.peak {
+		p.peak = p.inFlight
+	}
+	p.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	p.mu.Lock()
+	p.inFlight--
+	p.mu.Unlock()
+	return nil, ErrNoLyrics
+}
+
+func TestGetLyrics_BoundsConcurrentFetches(t *testing.T) {
+	provider := &concurrencyProvider{}
+	s := NewWithProviders(cache.New(10), provider)
+	s.SetMaxConcurrentFetches(2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("track%d", i)
+			s.GetLyrics(context.Background(), id, "Artist", id)
+		}(i)
+	}
+	wg.Wait()
+
+	if provider.peak > 2 {
+		t.Errorf("Peak concurrent lookups = %d; want at most 2", provider.peak)
+	}
+}
+
+func TestGetLyrics_FetchSlotHonorsDeadline(t *testing.T) {
+	s := NewWithProviders(cache.New(10), &mockProvider{name: "Mock"})
+	s.SetMaxConcurrentFetches(1)
+	s.SetTotalTimeout(50 * time.Millisecond)
+
+	// Hold the only slot so the lookup has to wait past its deadline
+	release, err := s.acquireFetchSlot(context.Background())
+	if err != nil {
+		t.Fatalf("acquireFetchSlot failed: %v", err)
+	}
+	defer release()
+
+	if _, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title"); !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("GetLyrics error = %v; want ErrProviderUnavailable", err)
+	}
+}
+
+func TestSetProviderEnabled(t *testing.T) {
+	first := &mockProvider{name: "First", result: &overlay.LyricsData{Source: "First", Lines: []overlay.LyricsLine{{Text: "first"}}}}
+	second := &mockProvider{name: "Second", result: &overlay.LyricsData{Source: "Second", Lines: []overlay.LyricsLine{{Text: "second"}}}}
+	s := NewWithProviders(cache.New(10), first, second)
+
+	name, err := s.SetProviderEnabled("first", false)
+	if err != nil || name != "First" {
+		t.Fatalf("SetProviderEnabled = %q, %v; want First", name, err)
+	}
+	lyrics, err := s.GetLyrics(context.Background(), "track1", "Artist", "Title")
+	if err != nil || lyrics.Source != "Second" {
+		t.Fatalf("GetLyrics = %+v, %v; want the second provider", lyrics, err)
+	}
+	if first.calls != 0 {
+		t.Errorf("Disabled provider was called %d times", first.calls)
+	}
+
+	want := []ProviderInfo{{Name: "First", Enabled: false}, {Name: "Second", Enabled: true}}
+	if got := s.Providers(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Providers = %+v; want %+v", got, want)
+	}
+
+	if _, err := s.SetProviderEnabled("First", true); err != nil {
+		t.Fatalf("SetProviderEnabled failed: %v", err)
+	}
+	if lyrics, _ := s.GetLyrics(context.Background(), "track2", "Artist", "Other"); lyrics == nil || lyrics.Source != "First" {
+		t.Errorf("Expected the re-enabled provider to answer, got %+v", lyrics)
+	}
+
+	if _, err := s.SetProviderEnabled("Missing", false); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+}
+
+func TestGetLyricsForTrack_RejectsLyricsOverrunningTrack(t *testing.T) {
+	// Last line at 6:00 for a 3:00 track: lyrics for a different (extended) recording
+	overrun := &overlay.LyricsData{
+		Source:   "Overrun",
+		IsSynced: true,
+		Lines:    parseLRCToLines("[00:10.00]Intro\n[03:00.00]Middle\n[06:00.00]Outro"),
+	}
+	fitting := &overlay.LyricsData{
+		Source:   "Fitting",
+		IsSynced: true,
+		Lines:    parseLRCToLines("[00:10.00]Intro\n[02:50.00]Outro"),
+	}
+	track := &overlay.TrackInfo{ID: "track1", Name: "Title", Artists: []string{"Artist"}, Duration: 180000}
+
+	s := NewWithProviders(cache.New(10), &mockProvider{name: "Overrun", result: overrun}, &mockProvider{name: "Fitting", result: fitting})
+	lyrics, err := s.GetLyricsForTrack(context.Background(), track)
+	if err != nil || lyrics.Source != "Fitting" {
+		t.Fatalf("GetLyricsForTrack = %+v, %v; want the next provider's lyrics", lyrics, err)
+	}
+
+	// With nothing better, the overrunning lyrics are used with lowered confidence
+	s = NewWithProviders(cache.New(10), &mockProvider{name: "Overrun", result: overrun}, NewDemoProvider())
+	lyrics, err = s.GetLyricsForTrack(context.Background(), track)
+	if err != nil || lyrics.Source != "Overrun" {
+		t.Fatalf("GetLyricsForTrack = %+v, %v; want the overrunning lyrics as a last resort", lyrics, err)
+	}
+	if lyrics.MatchConfidence > 0.5 {
+		t.Errorf("MatchConfidence = %.2f; want it lowered for a likely mismatch", lyrics.MatchConfidence)
+	}
+}
+
+func TestHasLyrics(t *testing.T) {
+	found := &mockProvider{name: "Mock", result: &overlay.LyricsData{Source: "Mock", IsSynced: true, Lines: []overlay.LyricsLine{{Text: "line", Timestamp: 1000}}}}
+	s := NewWithProviders(cache.New(10), found)
+
+	if s.HasLyrics(context.Background(), "track1", "Artist", "Title", false) {
+		t.Error("Expected no lyrics without a probe or cache entry")
+	}
+	if !s.HasLyrics(context.Background(), "track1", "Artist", "Title", true) {
+		t.Fatal("Expected the probe to find lyrics")
+	}
+	if !s.HasLyrics(context.Background(), "track1", "Artist", "Title", true) || found.calls != 1 {
+		t.Errorf("Provider calls = %d; want the second check answered from cache", found.calls)
+	}
+
+	// A miss is remembered so repeated checks don't query providers again
+	missing := &mockProvider{name: "Missing", err: ErrNoLyrics}
+	s = NewWithProviders(cache.New(10), missing)
+	for i := 0; i < 3; i++ {
+		if s.HasLyrics(context.Background(), "track2", "Artist", "Unknown", true) {
+			t.Fatal("Expected no lyrics for a track no provider has")
+		}
+	}
+	if missing.calls != 1 {
+		t.Errorf("Provider calls = %d; want 1 (negative cache)", missing.calls)
+	}
+}
+
+func TestNew_SearchLyricsWithStubbedLRCLIB(t *testing.T) {
+	tests := []struct {
+		name       string
+		get        string // /get response; empty for 404
+		search     string // /search?track_name=... response
+		query      string // /search?q=... response
+		wantSource string
+		wantKind   string
+		wantLine   string
+	}{
+		{
+			name:       "exact match",
+			get:        `{"id": 1, "trackName": "Song", "artistName": "Artist", "plainLyrics": "Exact"}`,
+			wantSource: "LRCLIB",
+			wantKind:   overlay.MatchExact,
+			wantLine:   "Exact",
+		},
+		{
+			name:       "search fallback",
+			search:     `[{"id": 2, "trackName": "Song", "artistName": "Artist", "plainLyrics": "Searched"}]`,
+			query:      `[]`,
+			wantSource: "LRCLIB",
+			wantKind:   overlay.MatchFuzzy,
+			wantLine:   "Searched",
+		},
+		{
+			name:       "query fallback",
+			search:     `[]`,
+			query:      `[{"id": 3, "trackName": "Song", "artistName": "Artist", "plainLyrics": "Queried"}]`,
+			wantSource: "LRCLIB",
+			wantKind:   overlay.MatchFallback,
+			wantLine:   "Queried",
+		},
+		{
+			name:       "empty results",
+			search:     `[]`,
+			query:      `[]`,
+			wantSource: "Info",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/get" && tt.get != "":
+					fmt.Fprint(w, tt.get)
+				case r.URL.Path == "/search" && r.URL.Query().Get("q") != "":
+					fmt.Fprint(w, tt.query)
+				case r.URL.Path == "/search":
+					fmt.Fprint(w, tt.search)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			s := New(cache.New(10), server.URL, server.Client())
+			data, err := s.SearchLyrics(context.Background(), "Artist", "Song")
+			if err != nil {
+				t.Fatalf("SearchLyrics failed: %v", err)
+			}
+			if data.Source != tt.wantSource || data.MatchKind != tt.wantKind {
+				t.Errorf("Got source %q kind %q; want %q/%q", data.Source, data.MatchKind, tt.wantSource, tt.wantKind)
+			}
+			if tt.wantLine != "" && (len(data.Lines) == 0 || data.Lines[0].Text != tt.wantLine) {
+				t.Errorf("Lines = %+v; want first line %q", data.Lines, tt.wantLine)
+			}
+		})
+	}
+}
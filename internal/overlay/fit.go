@@ -0,0 +1,59 @@
+package overlay
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// fitCharWidthRatio approximates the average glyph width as a fraction of the font size
+	fitCharWidthRatio = 0.6
+	// fitPadding covers the overlay's horizontal padding and border
+	fitPadding = 48
+	// MinFitWidth keeps auto-fit from shrinking the overlay for short lines
+	MinFitWidth = 240
+)
+
+// FitWidth estimates the window width needed to show each text on one line at fontSize.
+// Texts containing line breaks are sized to their widest segment.
+func FitWidth(texts []string, fontSize int) int {
+	longest := 0
+	for _, text := range texts {
+		for _, segment := range strings.Split(text, "\n") {
+			if n := utf8.RuneCountInString(strings.TrimSpace(segment)); n > longest {
+				longest = n
+			}
+		}
+	}
+
+	width := int(float64(longest)*float64(fontSize)*fitCharWidthRatio) + fitPadding
+	if width < MinFitWidth {
+		width = MinFitWidth
+	}
+	return width
+}
+
+// lineEllipsis marks a line cut short by TruncateLine
+const lineEllipsis = "…"
+
+// TruncateLine shortens text to at most maxChars runes, ending in "…". It cuts at the last word
+// boundary that fits, or mid-word when a single word is too long. maxChars <= 0 disables it.
+func TruncateLine(text string, maxChars int) string {
+	runes := []rune(text)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return text
+	}
+
+	// Leave room for the ellipsis
+	cut := maxChars - 1
+	if !unicode.IsSpace(runes[cut]) {
+		for i := cut - 1; i > 0; i-- {
+			if unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + lineEllipsis
+}
@@ -0,0 +1,60 @@
+package overlay
+
+import "testing"
+
+func TestFitWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		texts    []string
+		fontSize int
+		want     int
+	}{
+		{"short line uses minimum", []string{"Hi"}, 16, MinFitWidth},
+		{"longest line wins", []string{"short", "a much longer line of lyrics here"}, 20, int(33*20*fitCharWidthRatio) + fitPadding},
+		{"wrapped text uses widest segment", []string{"first part\nthe second, longer part"}, 20, int(23*20*fitCharWidthRatio) + fitPadding},
+		{"counts runes not bytes", []string{"日本語の歌詞がここにありますよ、長いですね、ほんとうに"}, 20, int(27*20*fitCharWidthRatio) + fitPadding},
+	}
+
+	for _, tt := range tests {
+		if got := FitWidth(tt.texts, tt.fontSize); got != tt.want {
+			t.Errorf("%s: FitWidth = %d; want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     string
+	}{
+		{"disabled", "a line that is quite long", 0, "a line that is quite long"},
+		{"fits", "short line", 10, "short line"},
+		{"cuts at a word boundary", "never gonna give you up", 16, "never gonna…"},
+		{"space right at the cut", "never gonna give you up", 12, "never gonna…"},
+		{"long word is cut mid-word", "supercalifragilistic", 8, "superca…"},
+		{"counts runes not bytes", "日本語の 歌詞がここに", 8, "日本語の…"},
+	}
+
+	for _, tt := range tests {
+		if got := TruncateLine(tt.text, tt.maxChars); got != tt.want {
+			t.Errorf("%s: TruncateLine = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetDisplayInfo_MaxLineChars(t *testing.T) {
+	s := newTestService(t)
+	s.config.Get().Overlay.MaxLineChars = 8
+	s.SetCurrentLyrics(&LyricsData{Source: "Test", Lines: []LyricsLine{{Text: "a rather long first line"}, {Text: "short"}}})
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000})
+
+	info := s.GetDisplayInfo()
+	if info.CurrentLine != "a…" {
+		t.Errorf("CurrentLine = %q; want %q", info.CurrentLine, "a…")
+	}
+	if info.NextLine != "short" {
+		t.Errorf("NextLine = %q; want it untouched", info.NextLine)
+	}
+}
@@ -0,0 +1,122 @@
+package overlay
+
+const (
+	// minOnScreen is how much of the window (px, each axis) must be on a screen to be reachable
+	minOnScreen = 40
+	// cornerMargin is the gap between a reset window and the screen edges
+	cornerMargin = 20
+)
+
+// ScreenBounds is a monitor's area in virtual desktop coordinates
+type ScreenBounds struct {
+	X, Y          int
+	Width, Height int
+	Primary       bool
+}
+
+// OnScreen reports whether enough of a window at x,y sized width x height is on some screen
+// to grab and drag it back
+func OnScreen(x, y, width, height int, screens []ScreenBounds) bool {
+	for _, screen := range screens {
+		overlapX := min(x+width, screen.X+screen.Width) - max(x, screen.X)
+		overlapY := min(y+height, screen.Y+screen.Height) - max(y, screen.Y)
+		if overlapX >= min(minOnScreen, width) && overlapY >= min(minOnScreen, height) {
+			return true
+		}
+	}
+	return false
+}
+
+// CoversScreen reports whether a window at x,y sized width x height covers all of screen, the
+// way full-screen apps (borderless or exclusive) size themselves
+func CoversScreen(x, y, width, height int, screen ScreenBounds) bool {
+	return screen.Width > 0 && screen.Height > 0 &&
+		x <= screen.X && y <= screen.Y &&
+		x+width >= screen.X+screen.Width && y+height >= screen.Y+screen.Height
+}
+
+// ClampXToScreen pulls a window at x,y sized width x height back inside the left and right
+// edges of the screen holding most of it. Windows on no screen are left where they are.
+func ClampXToScreen(x, y, width, height int, screens []ScreenBounds) int {
+	best, bestArea := -1, 0
+	for i, screen := range screens {
+		overlapX := min(x+width, screen.X+screen.Width) - max(x, screen.X)
+		overlapY := min(y+height, screen.Y+screen.Height) - max(y, screen.Y)
+		if overlapX > 0 && overlapY > 0 && overlapX*overlapY > bestArea {
+			best, bestArea = i, overlapX*overlapY
+		}
+	}
+	if best < 0 {
+		return x
+	}
+	screen := screens[best]
+	// A window wider than the screen starts at its left edge
+	return max(min(x, screen.X+screen.Width-width), screen.X)
+}
+
+// PositionOpposite as OverlayConfig.InGamePosition means the corner diagonally opposite Position
+const PositionOpposite = "opposite"
+
+// OppositeCorner returns the corner diagonally opposite position. Unknown positions are
+// treated as bottom-left, matching CornerPosition.
+func OppositeCorner(position string) string {
+	switch position {
+	case "top-left":
+		return "bottom-right"
+	case "top-right":
+		return "bottom-left"
+	case "bottom-right":
+		return "top-left"
+	default:
+		return "top-right"
+	}
+}
+
+// CornerPosition places a window in the primary screen's corner named by position
+// ("top-left", "top-right", "bottom-left", "bottom-right"; anything else is bottom-left)
+func CornerPosition(position string, width, height int, screens []ScreenBounds) (int, int) {
+	if len(screens) == 0 {
+		return cornerMargin, cornerMargin
+	}
+	screen := screens[0]
+	for _, candidate := range screens {
+		if candidate.Primary {
+			screen = candidate
+			break
+		}
+	}
+
+	left := screen.X + cornerMargin
+	right := screen.X + screen.Width - width - cornerMargin
+	top := screen.Y + cornerMargin
+	bottom := screen.Y + screen.Height - height - cornerMargin
+	// A window larger than the screen still starts at its top-left edge
+	right = max(right, left)
+	bottom = max(bottom, top)
+
+	switch position {
+	case "top-left":
+		return left, top
+	case "top-right":
+		return right, top
+	case "bottom-right":
+		return right, bottom
+	default:
+		return left, bottom
+	}
+}
+
+// EnsureOnScreen checks the saved overlay geometry against screens and, if the window would be
+// unreachable (e.g. its monitor was unplugged), moves it to the Position corner of the primary
+// screen and saves. Returns true if the position was reset.
+func (s *Service) EnsureOnScreen(screens []ScreenBounds) (bool, error) {
+	if len(screens) == 0 {
+		return false, nil
+	}
+	overlayConfig := s.config.Overlay()
+	if OnScreen(overlayConfig.X, overlayConfig.Y, overlayConfig.Width, overlayConfig.Height, screens) {
+		return false, nil
+	}
+	overlayConfig.X, overlayConfig.Y = CornerPosition(overlayConfig.Position, overlayConfig.Width, overlayConfig.Height, screens)
+	return true, s.config.UpdateOverlay(overlayConfig)
+}
@@ -0,0 +1,144 @@
+package overlay
+
+import "testing"
+
+func TestOnScreen(t *testing.T) {
+	screens := []ScreenBounds{
+		{Width: 1920, Height: 1080, Primary: true},
+		{X: 1920, Width: 2560, Height: 1440},
+	}
+
+	tests := []struct {
+		name string
+		x, y int
+		want bool
+	}{
+		{"primary", 100, 100, true},
+		{"second monitor", 3000, 1200, true},
+		{"mostly off the right edge", 4430, 100, true},
+		{"past the right edge", 4460, 100, false},
+		{"unplugged left monitor", -1500, 300, false},
+		{"below every screen", 100, 1500, false},
+	}
+
+	for _, tt := range tests {
+		if got := OnScreen(tt.x, tt.y, 600, 120, screens); got != tt.want {
+			t.Errorf("%s: OnScreen(%d, %d) = %v; want %v", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestClampXToScreen(t *testing.T) {
+	screens := []ScreenBounds{
+		{X: -2560, Y: -200, Width: 2560, Height: 1440},
+		{Width: 1920, Height: 1080, Primary: true},
+	}
+
+	tests := []struct {
+		name  string
+		x, y  int
+		width int
+		want  int
+	}{
+		{"inside primary", 100, 100, 600, 100},
+		{"past primary right edge", 1500, 100, 600, 1320},
+		{"inside left monitor", -2000, 0, 600, -2000},
+		{"past left monitor's left edge", -2700, 0, 600, -2560},
+		{"mostly on left monitor, over the seam", -400, 0, 600, -600},
+		{"wider than the screen", 100, 100, 2000, 0},
+		{"on no screen", 5000, 100, 600, 5000},
+	}
+
+	for _, tt := range tests {
+		if got := ClampXToScreen(tt.x, tt.y, tt.width, 120, screens); got != tt.want {
+			t.Errorf("%s: ClampXToScreen(%d, %d) = %d; want %d", tt.name, tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestCornerPosition(t *testing.T) {
+	screens := []ScreenBounds{
+		{X: 1920, Width: 2560, Height: 1440},
+		{Width: 1920, Height: 1080, Primary: true},
+	}
+
+	tests := []struct {
+		position string
+		wantX    int
+		wantY    int
+	}{
+		{"top-left", 20, 20},
+		{"top-right", 1300, 20},
+		{"bottom-left", 20, 940},
+		{"bottom-right", 1300, 940},
+		{"", 20, 940},
+	}
+
+	for _, tt := range tests {
+		x, y := CornerPosition(tt.position, 600, 120, screens)
+		if x != tt.wantX || y != tt.wantY {
+			t.Errorf("CornerPosition(%q) = (%d, %d); want (%d, %d)", tt.position, x, y, tt.wantX, tt.wantY)
+		}
+	}
+}
+
+func TestEnsureOnScreen(t *testing.T) {
+	service := newTestService(t)
+	screens := []ScreenBounds{{Width: 1920, Height: 1080, Primary: true}}
+
+	moved, err := service.EnsureOnScreen(screens)
+	if err != nil || moved {
+		t.Fatalf("EnsureOnScreen with default geometry = %v, %v; want no move", moved, err)
+	}
+
+	// Saved on an external monitor that is no longer connected
+	overlayConfig := service.GetOverlayConfig()
+	overlayConfig.X, overlayConfig.Y = 2500, 400
+	overlayConfig.Position = "top-right"
+	if err := service.UpdateOverlayConfig(overlayConfig); err != nil {
+		t.Fatalf("UpdateOverlayConfig failed: %v", err)
+	}
+
+	moved, err = service.EnsureOnScreen(screens)
+	if err != nil || !moved {
+		t.Fatalf("EnsureOnScreen off-screen = %v, %v; want a move", moved, err)
+	}
+	got := service.GetOverlayConfig()
+	if got.X != 1920-600-cornerMargin || got.Y != cornerMargin {
+		t.Errorf("Reset position = (%d, %d); want top-right corner", got.X, got.Y)
+	}
+}
+
+func TestOppositeCorner(t *testing.T) {
+	tests := map[string]string{
+		"top-left":     "bottom-right",
+		"top-right":    "bottom-left",
+		"bottom-left":  "top-right",
+		"bottom-right": "top-left",
+		"":             "top-right",
+	}
+	for position, want := range tests {
+		if got := OppositeCorner(position); got != want {
+			t.Errorf("OppositeCorner(%q) = %q; want %q", position, got, want)
+		}
+	}
+}
+
+func TestCoversScreen(t *testing.T) {
+	second := ScreenBounds{X: 1920, Y: 0, Width: 2560, Height: 1440}
+	tests := []struct {
+		name                string
+		x, y, width, height int
+		want                bool
+	}{
+		{"exact fit", 1920, 0, 2560, 1440, true},
+		{"exclusive window overhanging the edges", 1912, -8, 2576, 1456, true},
+		{"maximized above the taskbar", 1920, 0, 2560, 1400, false},
+		{"on another monitor", 0, 0, 1920, 1080, false},
+	}
+	for _, tt := range tests {
+		if got := CoversScreen(tt.x, tt.y, tt.width, tt.height, second); got != tt.want {
+			t.Errorf("%s: CoversScreen = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package overlay
+
+// maxLineHoldLagMs caps how far a held line may fall behind the line that should be showing,
+// so sustained fast sections can't drift further and further out of sync
+const maxLineHoldLagMs int64 = 1500
+
+// lineHold is the synced line on screen for Overlay.MinLineDisplayMs
+type lineHold struct {
+	trackID string
+	index   int   // Line index being shown
+	shownAt int64 // Lyrics progress when it appeared
+}
+
+// next returns the line to show when the timestamps say idx. The shown line stays until it has
+// been up for minMs, then the following line is shown (one at a time, so none are skipped),
+// unless the display would be more than maxLineHoldLagMs behind, in which case it jumps to
+// idx. minMs <= 0, a new track or a backwards seek show idx right away.
+func (h *lineHold) next(trackID string, lines []LyricsLine, idx int, progress, minMs int64, skipEmpty bool) int {
+	if minMs <= 0 || idx < 0 || h.trackID != trackID || h.index < 0 || idx < h.index || h.index >= len(lines) {
+		*h = lineHold{trackID: trackID, index: idx, shownAt: progress}
+		return idx
+	}
+	if idx == h.index {
+		return idx
+	}
+
+	shown := h.index
+	if progress-h.shownAt >= minMs {
+		shown = nextShownLine(lines, h.index, idx, skipEmpty)
+	}
+	if shown != idx && progress-lines[nextShownLine(lines, shown, idx, skipEmpty)].Timestamp > maxLineHoldLagMs {
+		shown = idx
+	}
+	if shown != h.index {
+		h.index, h.shownAt = shown, progress
+	}
+	return shown
+}
+
+// nextShownLine returns the first line after from, up to limit, that would be displayed
+func nextShownLine(lines []LyricsLine, from, limit int, skipEmpty bool) int {
+	for j := from + 1; j < limit; j++ {
+		if !skipEmpty || lines[j].Text != "" {
+			return j
+		}
+	}
+	return limit
+}
+
+// holdLine applies Overlay.MinLineDisplayMs to the current synced line
+func (s *Service) holdLine(lines []LyricsLine, idx int, progress int64, skipEmpty bool) int {
+	s.holdMu.Lock()
+	defer s.holdMu.Unlock()
+	return s.hold.next(s.currentTrack.ID, lines, idx, progress, int64(s.config.Overlay().MinLineDisplayMs), skipEmpty)
+}
@@ -0,0 +1,82 @@
+package overlay
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLineHold(t *testing.T) {
+	// A rap-style burst: lines 300ms apart
+	lines := []LyricsLine{
+		{Text: "One", Timestamp: 1000},
+		{Text: "Two", Timestamp: 1300},
+		{Text: "Three", Timestamp: 1600},
+		{Text: "Four", Timestamp: 1900},
+		{Text: "", Timestamp: 2200},
+		{Text: "Five", Timestamp: 2500},
+	}
+
+	steps := []struct {
+		name     string
+		idx      int
+		progress int64
+		want     int
+	}{
+		{"first line shows immediately", 0, 1000, 0},
+		{"next line waits for the minimum", 1, 1300, 0},
+		{"still held", 2, 1600, 0},
+		{"released one line at a time", 3, 1900, 1},
+		{"lag past the cap jumps ahead", 3, 3500, 3},
+		{"held again after the jump", 5, 3600, 3},
+		{"empty lines are skipped", 5, 4400, 5},
+		{"seeking back shows the line right away", 0, 1000, 0},
+	}
+
+	var h lineHold
+	for _, step := range steps {
+		if got := h.next("track", lines, step.idx, step.progress, 800, true); got != step.want {
+			t.Errorf("%s: shown line = %d; want %d", step.name, got, step.want)
+		}
+	}
+
+	// Disabled, or a different track, follows the timestamps exactly
+	h = lineHold{trackID: "track", index: 0, shownAt: 1000}
+	if got := h.next("track", lines, 2, 1600, 0, true); got != 2 {
+		t.Errorf("Disabled hold showed line %d; want 2", got)
+	}
+	h = lineHold{trackID: "track", index: 0, shownAt: 1000}
+	if got := h.next("other", lines, 2, 1600, 800, true); got != 2 {
+		t.Errorf("New track showed line %d; want 2", got)
+	}
+}
+
+func TestGetDisplayInfo_MinLineDisplay(t *testing.T) {
+	s := newTestService(t)
+	s.config.Get().Overlay.SyncOffset = -1 // Effectively no lead, so progress maps straight to lines
+	s.config.Get().Overlay.MinLineDisplayMs = 800
+	s.SetCurrentLyrics(&LyricsData{
+		Source:   "Test",
+		IsSynced: true,
+		Lines: []LyricsLine{
+			{Text: "Fast one", Timestamp: 10000},
+			{Text: "Fast two", Timestamp: 10300},
+			{Text: "Later", Timestamp: 60000},
+		},
+	})
+
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 10001, UpdatedAt: time.Now()})
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Fast one" {
+		t.Fatalf("CurrentLine = %q; want %q", info.CurrentLine, "Fast one")
+	}
+
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 10301, UpdatedAt: time.Now()})
+	info := s.GetDisplayInfo()
+	if info.CurrentLine != "Fast one" || info.NextLine != "Fast two" {
+		t.Errorf("Held display = %q / %q; want %q / %q", info.CurrentLine, info.NextLine, "Fast one", "Fast two")
+	}
+
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 10801, UpdatedAt: time.Now()})
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Fast two" {
+		t.Errorf("CurrentLine after the hold = %q; want %q", info.CurrentLine, "Fast two")
+	}
+}
@@ -0,0 +1,58 @@
+package overlay
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// defaultProfanity is the built-in list of words masked for clean track versions
+var defaultProfanity = []string{
+	"fuck", "shit", "bitch", "cunt", "dick", "pussy", "asshole", "bastard", "motherfucker", "nigga",
+}
+
+var (
+	profanityMu      sync.RWMutex
+	profanityPattern = compileProfanity(defaultProfanity)
+)
+
+// SetProfanityList replaces the words masked for clean tracks (nil restores the built-in list)
+func SetProfanityList(words []string) {
+	if words == nil {
+		words = defaultProfanity
+	}
+	pattern := compileProfanity(words)
+
+	profanityMu.Lock()
+	defer profanityMu.Unlock()
+	profanityPattern = pattern
+}
+
+// compileProfanity builds a case-insensitive whole-word pattern, allowing common suffixes
+func compileProfanity(words []string) *regexp.Regexp {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)(?:s|es|ed|er|ers|ing|in'?)?\b`)
+}
+
+// maskProfanity replaces listed words with their first letter followed by asterisks
+func maskProfanity(text string) string {
+	profanityMu.RLock()
+	pattern := profanityPattern
+	profanityMu.RUnlock()
+	if pattern == nil || text == "" {
+		return text
+	}
+
+	return pattern.ReplaceAllStringFunc(text, func(word string) string {
+		runes := []rune(word)
+		return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+	})
+}
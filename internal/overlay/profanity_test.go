@@ -0,0 +1,56 @@
+package overlay
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaskProfanity(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"What the fuck", "What the f***"},
+		{"Shit, SHIT!", "S***, S***!"},
+		{"Fucking around", "F****** around"},
+		{"Dickens wrote classics", "Dickens wrote classics"}, // Only whole words
+		{"Bass and grass", "Bass and grass"},
+		{"", ""},
+	}
+
+	for _, tc := range tests {
+		if got := maskProfanity(tc.input); got != tc.want {
+			t.Errorf("maskProfanity(%q) = %q; want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestSetProfanityList(t *testing.T) {
+	defer SetProfanityList(nil)
+
+	SetProfanityList([]string{"heck"})
+	if got := maskProfanity("Oh heck, shit"); got != "Oh h***, shit" {
+		t.Errorf("Custom list mask = %q; want %q", got, "Oh h***, shit")
+	}
+
+	SetProfanityList([]string{})
+	if got := maskProfanity("Oh heck"); got != "Oh heck" {
+		t.Errorf("Empty list mask = %q; want unchanged", got)
+	}
+}
+
+func TestGetDisplayInfo_MasksCleanTracks(t *testing.T) {
+	s := newTestService(t)
+	s.config.Get().MaskProfanityForClean = true
+	s.SetCurrentLyrics(&LyricsData{Source: "Test", Lines: []LyricsLine{{Text: "Oh shit"}, {Text: "Fine line"}}})
+
+	s.SetCurrentTrack(&TrackInfo{ID: "clean", Explicit: false, UpdatedAt: time.Now()})
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Oh s***" {
+		t.Errorf("Clean track CurrentLine = %q; want masked", info.CurrentLine)
+	}
+
+	s.SetCurrentTrack(&TrackInfo{ID: "explicit", Explicit: true, UpdatedAt: time.Now()})
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Oh shit" {
+		t.Errorf("Explicit track CurrentLine = %q; want unmasked", info.CurrentLine)
+	}
+}
@@ -1,8 +1,15 @@
 package overlay
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"lyrics-overlay/internal/config"
 )
@@ -10,16 +17,52 @@ import (
 // Service manages the overlay window and lyrics display
 type Service struct {
 	config        *config.Service
+	ctx           context.Context // Wails runtime context; nil outside the app (e.g. tests)
 	mu            sync.RWMutex
 	currentTrack  *TrackInfo
 	currentLyrics *LyricsData
+	reviewLyrics  *LyricsData // Read-only lyrics shown instead of the playing track
+	pendingLyrics *LyricsData // Low-confidence match awaiting ConfirmLyrics (Config.AutoApplyConfidence)
+	pendingID     string      // Track the pending lyrics belong to
 	isVisible     bool
+	autoHidden    map[string]bool // Reasons the overlay is hidden automatically, not by the user
 	lastUpdate    time.Time
+
+	// Freeze holds the lyrics at the progress captured when the user froze the display
+	frozen         bool
+	frozenTrackID  string
+	frozenProgress int64
+
+	// Linger keeps showing the last display after playback stops, until lingerUntil
+	lingerInfo  *DisplayInfo
+	lingerUntil time.Time
+
+	// Differences between extrapolated and reported progress, for GetSyncQuality
+	syncSamples []int64
+
+	// Progress smoothing state; separate lock since it's updated while reading the display
+	smoothMu   sync.Mutex
+	pollWindow time.Duration
+	floor      progressFloor
+
+	// Minimum line display state; also updated while reading the display
+	holdMu sync.Mutex
+	hold   lineHold
 }
 
+// Auto-hide reasons
+const (
+	AutoHideNoLyrics   = "no-lyrics"    // The track has no lyrics
+	AutoHideForApp     = "hide-for-app" // An app from Config.HideForApps is focused
+	AutoHideFullscreen = "fullscreen"   // A full-screen app is in the foreground (Config.HideOnFullscreen)
+)
+
 // defaultSyncLeadMs is the default offset if not configured.
 const defaultSyncLeadMs int64 = 350
 
+// trackEndWindowMs is how close to the end of a track progress stops advancing.
+const trackEndWindowMs int64 = 1000
+
 // TrackInfo holds information about the currently playing track
 type TrackInfo struct {
 	ID        string    `json:"id"`
@@ -30,33 +73,100 @@ type TrackInfo struct {
 	Progress  int64     `json:"progress_ms"`
 	IsPlaying bool      `json:"is_playing"`
 	UpdatedAt time.Time `json:"updated_at"`
+	ISRC      string    `json:"isrc,omitempty"` // Identifies the exact recording, when Spotify provides it
+	Explicit  bool      `json:"explicit"`       // Spotify marks the track as explicit (false for clean versions)
+
+	// Device the track is playing on
+	DeviceID   string `json:"device_id,omitempty"`
+	DeviceName string `json:"device_name,omitempty"`
 }
 
 // LyricsData holds lyrics information
 type LyricsData struct {
-	TrackID   string       `json:"track_id"`
-	Source    string       `json:"source"`
-	Lines     []LyricsLine `json:"lines"`
-	IsSynced  bool         `json:"is_synced"`
-	FetchedAt time.Time    `json:"fetched_at"`
+	TrackID        string       `json:"track_id"`
+	Source         string       `json:"source"`
+	Lines          []LyricsLine `json:"lines"`
+	IsSynced       bool         `json:"is_synced"`
+	IsInstrumental bool         `json:"is_instrumental"` // Provider confirmed the track has no lyrics
+	FetchedAt      time.Time    `json:"fetched_at"`
+
+	// Track the provider matched, and how confident we are it is the playing track (0-1)
+	MatchedArtist   string  `json:"matched_artist,omitempty"`
+	MatchedTitle    string  `json:"matched_title,omitempty"`
+	MatchConfidence float64 `json:"match_confidence"`
+	// Album and length of the matched record, when the provider reports them
+	MatchedAlbum      string `json:"matched_album,omitempty"`
+	MatchedDurationMs int64  `json:"matched_duration_ms,omitempty"`
+	// How the provider picked the track, if it reports it: MatchExact, MatchFuzzy or MatchFallback
+	MatchKind  string `json:"match_kind,omitempty"`
+	MatchScore int    `json:"match_score"` // Provider's own ranking score for the pick
+
+	// Web page for the lyrics at the provider, if known
+	SourceURL string `json:"source_url,omitempty"`
+	// Served from the lyrics cache rather than fetched from Source just now
+	FromCache bool `json:"from_cache"`
 }
 
+// How a provider resolved the track it returned lyrics for
+const (
+	MatchExact    = "exact"    // Provider looked the track up directly
+	MatchFuzzy    = "fuzzy"    // Best-scoring search result
+	MatchFallback = "fallback" // First result of a loose query; likely wrong
+)
+
 // LyricsLine represents a single line of lyrics
 type LyricsLine struct {
+	Text      string       `json:"text"`
+	Timestamp int64        `json:"timestamp_ms,omitempty"` // For synced lyrics
+	Words     []LyricsWord `json:"words,omitempty"`        // Word timings from enhanced LRC, if any
+
+	SecondaryText string `json:"secondary_text,omitempty"` // Translation sharing the timestamp (bilingual LRC)
+	IsSection     bool   `json:"is_section,omitempty"`     // Structure marker like "[Chorus]", not sung
+}
+
+// LyricsWord is a single timed word within a line
+type LyricsWord struct {
 	Text      string `json:"text"`
-	Timestamp int64  `json:"timestamp_ms,omitempty"` // For synced lyrics
+	Timestamp int64  `json:"timestamp_ms"`
+	End       int64  `json:"end_ms,omitempty"` // From a closing tag on the last word; 0 runs to the line's end
+}
+
+// WordTiming is the span of a word in the current line, for per-word fades
+type WordTiming struct {
+	Text    string `json:"text"`
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
 }
 
 // New creates a new overlay service
 func New(configSvc *config.Service) (*Service, error) {
 	service := &Service{
-		config:    configSvc,
-		isVisible: configSvc.Get().Overlay.Visible,
+		config:     configSvc,
+		isVisible:  configSvc.Overlay().Visible,
+		autoHidden: make(map[string]bool),
 	}
 
 	return service, nil
 }
 
+// SetContext provides the Wails runtime context used to emit events and query the window
+func (s *Service) SetContext(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx = ctx
+}
+
+// emit sends an event to the frontend; a no-op without a runtime context
+func (s *Service) emit(event string, data ...interface{}) {
+	s.mu.RLock()
+	ctx := s.ctx
+	s.mu.RUnlock()
+	if ctx == nil {
+		return
+	}
+	runtime.EventsEmit(ctx, event, data...)
+}
+
 // GetCurrentTrack returns the currently playing track information
 func (s *Service) GetCurrentTrack() *TrackInfo {
 	s.mu.RLock()
@@ -68,8 +178,41 @@ func (s *Service) GetCurrentTrack() *TrackInfo {
 func (s *Service) SetCurrentTrack(track *TrackInfo) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	// A frozen line from a previous song is meaningless; resume following playback
+	if s.frozen && (track == nil || track.ID != s.frozenTrackID) {
+		s.frozen = false
+	}
+	if track == nil || track.ID != s.pendingID {
+		s.pendingLyrics, s.pendingID = nil, ""
+	}
+	s.recordSyncSampleLocked(s.currentTrack, track)
 	s.currentTrack = track
 	s.lastUpdate = time.Now()
+	s.lingerInfo = nil
+}
+
+// EndTrack clears the current track when playback stops. If Overlay.LingerSeconds is set,
+// the last displayed line stays up for that long before "No track playing" is shown.
+func (s *Service) EndTrack() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.currentTrack == nil {
+		return // Already stopped; keep any linger running
+	}
+
+	var lingerInfo *DisplayInfo
+	if linger := s.config.Overlay().LingerSeconds; linger > 0 && s.reviewLyrics == nil && s.currentLyrics.HasLyrics() {
+		lingerInfo = s.displayInfoLocked()
+		lingerInfo.IsPlaying = false
+		lingerInfo.Lingering = true
+		s.lingerUntil = time.Now().Add(time.Duration(linger) * time.Second)
+	}
+
+	s.frozen = false
+	s.currentTrack = nil
+	s.lastUpdate = time.Now()
+	s.lingerInfo = lingerInfo
 }
 
 // GetCurrentLyrics returns the current lyrics
@@ -82,15 +225,227 @@ func (s *Service) GetCurrentLyrics() *LyricsData {
 // SetCurrentLyrics updates the current lyrics
 func (s *Service) SetCurrentLyrics(lyrics *LyricsData) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.setCurrentLyricsLocked(lyrics)
+	s.mu.Unlock()
+
+	s.emit("lyrics:updated", lyrics.HasLyrics())
+}
+
+// SetLyricsForTrack sets the lyrics only if trackID is still the current track, so a slow
+// lookup can't overwrite the lyrics of a newer track. It reports whether they were accepted.
+// Matches less confident than Config.AutoApplyConfidence are held for ConfirmLyrics instead
+// of shown, and "lyrics:confirm" is emitted so the UI can prompt.
+func (s *Service) SetLyricsForTrack(trackID string, lyrics *LyricsData) bool {
+	s.mu.Lock()
+	if s.currentTrack == nil || s.currentTrack.ID != trackID {
+		s.mu.Unlock()
+		return false
+	}
+	if threshold := s.config.Get().AutoApplyConfidence; threshold > 0 && lyrics.HasLyrics() && lyrics.MatchConfidence < threshold {
+		s.currentLyrics = nil
+		s.setAutoHiddenLocked(AutoHideNoLyrics, false)
+		s.pendingLyrics, s.pendingID = lyrics, trackID
+		s.mu.Unlock()
+
+		s.emit("lyrics:confirm", lyrics)
+		return true
+	}
+	s.setCurrentLyricsLocked(lyrics)
+	s.mu.Unlock()
+
+	s.emit("lyrics:updated", lyrics.HasLyrics())
+	return true
+}
+
+// PendingLyrics returns the low-confidence match awaiting confirmation, or nil
+func (s *Service) PendingLyrics() *LyricsData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pendingLyrics
+}
+
+// ConfirmLyrics shows the pending low-confidence match for the current track. It reports
+// whether there was one to confirm.
+func (s *Service) ConfirmLyrics() bool {
+	s.mu.Lock()
+	lyrics := s.pendingLyrics
+	if lyrics == nil || s.currentTrack == nil || s.currentTrack.ID != s.pendingID {
+		s.mu.Unlock()
+		return false
+	}
+	s.setCurrentLyricsLocked(lyrics)
+	s.mu.Unlock()
+
+	s.emit("lyrics:updated", lyrics.HasLyrics())
+	return true
+}
+
+// setCurrentLyricsLocked stores the lyrics, dropping any pending match, and updates auto-hide
+// (must hold write lock)
+func (s *Service) setCurrentLyricsLocked(lyrics *LyricsData) {
 	s.currentLyrics = lyrics
+	s.pendingLyrics, s.pendingID = nil, ""
+
+	// Optionally hide for instrumentals and unmatched tracks, restoring once lyrics return
+	hide := s.config.Get().HideWhenNoLyrics && !lyrics.HasLyrics()
+	s.setAutoHiddenLocked(AutoHideNoLyrics, hide)
+}
+
+// lyricsVariant names the provider and whether its synced or plain lyrics are shown
+func lyricsVariant(lyrics *LyricsData) string {
+	if !lyrics.HasLyrics() {
+		return lyrics.Source
+	}
+	if lyrics.IsSynced {
+		return lyrics.Source + " (synced)"
+	}
+	return lyrics.Source + " (plain)"
+}
+
+// HasLyrics reports whether the data holds real lyrics rather than a placeholder or instrumental
+func (l *LyricsData) HasLyrics() bool {
+	if l == nil || l.IsInstrumental || len(l.Lines) == 0 {
+		return false
+	}
+	return !strings.EqualFold(l.Source, "Info") && !strings.EqualFold(l.Source, "Demo")
 }
 
-// GetDisplayInfo returns the current lyrics lines to display
+// ShowReadOnlyLyrics displays the given lyrics without following playback
+func (s *Service) ShowReadOnlyLyrics(lyrics *LyricsData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reviewLyrics = lyrics
+}
+
+// ClearReadOnlyLyrics returns the display to the currently playing track
+func (s *Service) ClearReadOnlyLyrics() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reviewLyrics = nil
+}
+
+// IsReadOnly returns whether read-only lyrics are being displayed
+func (s *Service) IsReadOnly() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.reviewLyrics != nil
+}
+
+// GetDisplayInfo returns the current lyrics lines to display; see GetDisplaySnapshot for the
+// complete view
 func (s *Service) GetDisplayInfo() *DisplayInfo {
+	return &s.GetDisplaySnapshot().DisplayInfo
+}
+
+// currentDisplayInfoLocked returns the lingering last line after playback stopped, else the
+// display info for the current state (must hold lock)
+func (s *Service) currentDisplayInfoLocked() *DisplayInfo {
+	if s.lingerInfo != nil && s.currentTrack == nil && s.reviewLyrics == nil && time.Now().Before(s.lingerUntil) {
+		info := *s.lingerInfo
+		info.Visible = s.isVisibleLocked()
+		return &info
+	}
+	return s.displayInfoLocked()
+}
+
+// displayInfoLocked builds the display info for the current state (must hold lock)
+func (s *Service) displayInfoLocked() *DisplayInfo {
+	info := s.buildDisplayInfo()
+	info.Visible = s.isVisibleLocked()
+	info.Frozen = s.frozen && s.reviewLyrics == nil
+	info.NeedsConfirmation = s.pendingLyrics != nil && s.reviewLyrics == nil
+	if s.reviewLyrics == nil && s.currentTrack != nil && s.currentLyrics != nil {
+		info.MatchConfidence = s.currentLyrics.MatchConfidence
+		info.Source = lyricsVariant(s.currentLyrics)
+		info.MatchKind = s.currentLyrics.MatchKind
+		info.MatchScore = s.currentLyrics.MatchScore
+	}
+	if s.reviewLyrics == nil && s.currentTrack != nil && s.config.Overlay().ShowProgress {
+		setProgressInfo(info, s.currentTrack, effectiveProgress(s.currentTrack, time.Now()))
+	}
+	// Providers often only have the explicit version; mask it for clean tracks if requested
+	if s.maskProfanityLocked() {
+		info.CurrentLine = maskProfanity(info.CurrentLine)
+		info.CurrentSecondary = maskProfanity(info.CurrentSecondary)
+		info.NextLine = maskProfanity(info.NextLine)
+	}
+	if maxChars := s.config.Overlay().MaxLineChars; maxChars > 0 {
+		info.CurrentLine = TruncateLine(info.CurrentLine, maxChars)
+		info.NextLine = TruncateLine(info.NextLine, maxChars)
+		info.HighlightIndex = min(info.HighlightIndex, utf8.RuneCountInString(info.CurrentLine))
+	}
+	return info
+}
+
+// maskProfanityLocked reports whether lyrics shown for the current track should be masked
+// (must hold lock)
+func (s *Service) maskProfanityLocked() bool {
+	return s.reviewLyrics == nil && s.currentTrack != nil && !s.currentTrack.Explicit && s.config.Get().MaskProfanityForClean
+}
+
+// ShareText returns a shareable "now playing" message with the current lyrics line, or just the
+// track when there are no lyrics to quote
+func (s *Service) ShareText() (string, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	track := s.currentTrack
+	if track == nil {
+		return "", fmt.Errorf("no track playing")
+	}
+
+	text := fmt.Sprintf("🎵 %s — %s", track.Name, strings.Join(track.Artists, ", "))
+	if s.reviewLyrics == nil && s.currentLyrics.HasLyrics() {
+		info := s.displayInfoLocked()
+		if info.CurrentLine != "" && !info.CurrentIsSection {
+			text += "\n> " + info.CurrentLine
+		}
+	}
+	return text, nil
+}
+
+// setProgressInfo fills the track position readout
+func setProgressInfo(info *DisplayInfo, track *TrackInfo, progress int64) {
+	info.ProgressText = formatTrackTime(progress)
+	info.DurationText = formatTrackTime(track.Duration)
+	if track.Duration > 0 {
+		info.ProgressPercent = float64(progress) * 100 / float64(track.Duration)
+	}
+}
+
+// formatTrackTime formats milliseconds as m:ss
+func formatTrackTime(ms int64) string {
+	if ms < 0 {
+		ms = 0
+	}
+	seconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// buildDisplayInfo computes the lines to display (must hold read lock)
+func (s *Service) buildDisplayInfo() *DisplayInfo {
+	// Read-only lyrics take precedence and never advance with playback
+	if s.reviewLyrics != nil && len(s.reviewLyrics.Lines) > 0 {
+		nextLine := ""
+		if len(s.reviewLyrics.Lines) > 1 {
+			nextLine = s.reviewLyrics.Lines[1].Text
+		}
+		return &DisplayInfo{
+			CurrentLine: s.reviewLyrics.Lines[0].Text,
+			NextLine:    nextLine,
+			IsPlaying:   false,
+			ReadOnly:    true,
+		}
+	}
+
+	if s.currentTrack != nil && s.pendingLyrics != nil {
+		return &DisplayInfo{
+			CurrentLine: "Lyrics found — confirm they match",
+			NextLine:    "",
+			IsPlaying:   s.currentTrack.IsPlaying,
+		}
+	}
+
 	if s.currentTrack == nil || s.currentLyrics == nil {
 		return &DisplayInfo{
 			CurrentLine: "No track playing",
@@ -99,68 +454,65 @@ func (s *Service) GetDisplayInfo() *DisplayInfo {
 		}
 	}
 
+	if s.currentLyrics.IsInstrumental {
+		return &DisplayInfo{
+			CurrentLine: "🎸 Instrumental",
+			NextLine:    "",
+			IsPlaying:   s.currentTrack.IsPlaying,
+		}
+	}
+
 	// For synced lyrics, find current line based on progress
 	if s.currentLyrics.IsSynced && len(s.currentLyrics.Lines) > 0 {
 		// Derive effective progress using last known Spotify progress + elapsed time
-		progress := s.currentTrack.Progress
-		if s.currentTrack.IsPlaying {
-			elapsed := time.Since(s.currentTrack.UpdatedAt).Milliseconds()
-			if elapsed > 0 {
-				progress += elapsed
-			}
+		progress := s.lyricsProgressLocked()
+
+		// Near the end of the track, hold the final line instead of extrapolating further
+		if s.currentTrack.Duration > 0 && progress >= s.currentTrack.Duration-trackEndWindowMs {
+			return finalLineInfo(s.currentLyrics.Lines, s.currentTrack.IsPlaying)
+		}
+
+		// Apply the track's saved offset, else the configurable global one (or default)
+		syncOffset, ok := s.config.TrackSyncOffset(s.currentTrack.ID)
+		if !ok {
+			syncOffset = s.config.Overlay().SyncOffset
 		}
-		// Apply configurable sync offset (or default)
-		syncOffset := s.config.Get().Overlay.SyncOffset
 		if syncOffset == 0 {
 			syncOffset = defaultSyncLeadMs
 		}
 		progress += syncOffset
-		currentIdx := -1
-
-		// Find the current lyrics line based on playback progress
-		for i, line := range s.currentLyrics.Lines {
-			if line.Timestamp <= progress {
-				currentIdx = i
-			} else {
-				break
+		lines := s.currentLyrics.Lines
+		skipEmpty := !s.config.Overlay().ShowEmptyLines
+		currentIdx, nextIdx := findCurrentAndNext(lines, progress, skipEmpty)
+
+		// Keep fast lines up long enough to read (Overlay.MinLineDisplayMs)
+		if shown := s.holdLine(lines, currentIdx, progress, skipEmpty); shown != currentIdx {
+			currentIdx, nextIdx = shown, -1
+			for j := shown + 1; j < len(lines); j++ {
+				if lines[j].Text != "" {
+					nextIdx = j
+					break
+				}
 			}
 		}
 
-		if currentIdx >= 0 && currentIdx < len(s.currentLyrics.Lines) {
-			currentLine := s.currentLyrics.Lines[currentIdx].Text
-			lineStartTime := s.currentLyrics.Lines[currentIdx].Timestamp
+		if currentIdx >= 0 {
+			current := lines[currentIdx]
+			currentLine := current.Text
+			lineStartTime := current.Timestamp
+			words := current.Words
+			secondary := current.SecondaryText
 			nextLine := ""
 			nextLineTime := int64(0)
-
-			// Find next non-empty line for preview and timing
-			for j := currentIdx + 1; j < len(s.currentLyrics.Lines); j++ {
-				if s.currentLyrics.Lines[j].Text != "" {
-					nextLine = s.currentLyrics.Lines[j].Text
-					nextLineTime = s.currentLyrics.Lines[j].Timestamp
-					break
-				} else if nextLineTime == 0 {
-					// Use empty line's timestamp for duration calc
-					nextLineTime = s.currentLyrics.Lines[j].Timestamp
-				}
-			}
-
-			// Skip empty lines for current line too
-			if currentLine == "" && currentIdx+1 < len(s.currentLyrics.Lines) {
-				for j := currentIdx + 1; j < len(s.currentLyrics.Lines); j++ {
-					if s.currentLyrics.Lines[j].Text != "" {
-						currentLine = s.currentLyrics.Lines[j].Text
-						lineStartTime = s.currentLyrics.Lines[j].Timestamp
-						// Update next line
-						for k := j + 1; k < len(s.currentLyrics.Lines); k++ {
-							if s.currentLyrics.Lines[k].Text != "" {
-								nextLine = s.currentLyrics.Lines[k].Text
-								nextLineTime = s.currentLyrics.Lines[k].Timestamp
-								break
-							}
-						}
-						break
-					}
+			if nextIdx >= 0 {
+				// Timing follows the very next line; the preview skips repeats of the current text
+				if previewIdx := distinctNextLine(lines, nextIdx, currentLine); previewIdx >= 0 {
+					nextLine = lines[previewIdx].Text
 				}
+				nextLineTime = lines[nextIdx].Timestamp
+			} else if currentIdx+1 < len(lines) {
+				// Only empty lines follow; use the first one's timestamp for duration calc
+				nextLineTime = lines[currentIdx+1].Timestamp
 			}
 
 			// Calculate line duration and progress
@@ -176,30 +528,57 @@ func (s *Service) GetDisplayInfo() *DisplayInfo {
 				lineProgress = lineDuration
 			}
 
-			return &DisplayInfo{
-				CurrentLine:   currentLine,
-				NextLine:      nextLine,
-				IsPlaying:     s.currentTrack.IsPlaying,
-				LineDuration:  lineDuration,
-				LineProgress:  lineProgress,
-				LineStartTime: lineStartTime,
+			info := &DisplayInfo{
+				CurrentLine:      currentLine,
+				CurrentSecondary: secondary,
+				NextLine:         nextLine,
+				IsPlaying:        s.currentTrack.IsPlaying,
+				LineDuration:     lineDuration,
+				LineProgress:     lineProgress,
+				LineStartTime:    lineStartTime,
+			}
+			if s.config.Overlay().WordTiming {
+				info.WordProgress = wordProgress(words, progress, lineStartTime+lineDuration, lineProgress, lineDuration)
+			} else {
+				words = nil
+			}
+			info.HighlightIndex = highlightIndex(currentLine, words, progress, lineProgress, lineDuration)
+			info.Words = wordTimings(currentLine, words, lineStartTime, lineStartTime+lineDuration)
+			return info
+		}
+
+		// Before the first line (a long intro): preview it with a countdown instead of
+		// showing it early
+		for _, line := range lines {
+			if line.Text != "" {
+				return introInfo(line, progress, s.currentTrack.IsPlaying)
 			}
 		}
 	}
 
+	// Plain lyrics may carry section headers like [Chorus]; some users prefer them hidden
+	plainLines := s.currentLyrics.Lines
+	if s.config.Get().HideSectionHeaders {
+		plainLines = withoutSections(plainLines)
+	}
+
+	// Optionally scroll plain lyrics using evenly spaced estimated timings
+	if !s.currentLyrics.IsSynced && s.config.Get().AutoAdvancePlain && s.currentTrack.Duration > 0 && len(plainLines) > 0 {
+		return plainLineInfo(plainLines, s.currentTrack, s.lyricsProgressLocked())
+	}
+
 	// For non-synced lyrics, show first few lines
-	if len(s.currentLyrics.Lines) > 0 {
-		currentLine := s.currentLyrics.Lines[0].Text
-		nextLine := ""
-		if len(s.currentLyrics.Lines) > 1 {
-			nextLine = s.currentLyrics.Lines[1].Text
+	if len(plainLines) > 0 {
+		info := &DisplayInfo{
+			CurrentLine:      plainLines[0].Text,
+			CurrentIsSection: plainLines[0].IsSection,
+			IsPlaying:        s.currentTrack.IsPlaying,
 		}
-
-		return &DisplayInfo{
-			CurrentLine: currentLine,
-			NextLine:    nextLine,
-			IsPlaying:   s.currentTrack.IsPlaying,
+		if len(plainLines) > 1 {
+			info.NextLine = plainLines[1].Text
+			info.NextIsSection = plainLines[1].IsSection
 		}
+		return info
 	}
 
 	return &DisplayInfo{
@@ -209,6 +588,302 @@ func (s *Service) GetDisplayInfo() *DisplayInfo {
 	}
 }
 
+// introMarker is shown while waiting for the first line
+const introMarker = "♪"
+
+// introInfo builds the display for progress before the first lyrics line
+func introInfo(first LyricsLine, progress int64, isPlaying bool) *DisplayInfo {
+	remaining := max(first.Timestamp-progress, 0)
+	return &DisplayInfo{
+		CurrentLine:      introMarker,
+		NextLine:         first.Text,
+		NextIsSection:    first.IsSection,
+		IsPlaying:        isPlaying,
+		LineDuration:     first.Timestamp,
+		LineProgress:     min(max(progress, 0), first.Timestamp),
+		Intro:            true,
+		IntroRemainingMs: remaining,
+	}
+}
+
+// FreezeDisplay holds the lyrics on the current line until UnfreezeDisplay, without pausing playback
+func (s *Service) FreezeDisplay() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.currentTrack == nil {
+		return false
+	}
+	s.frozen = true
+	s.frozenTrackID = s.currentTrack.ID
+	s.frozenProgress = effectiveProgress(s.currentTrack, time.Now())
+	return true
+}
+
+// UnfreezeDisplay resumes following playback
+func (s *Service) UnfreezeDisplay() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frozen = false
+}
+
+// IsFrozen returns whether the lyrics display is frozen
+func (s *Service) IsFrozen() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.frozen
+}
+
+// lyricsProgressLocked returns the progress used to pick lyrics lines (must hold read lock)
+func (s *Service) lyricsProgressLocked() int64 {
+	if s.frozen {
+		return s.frozenProgress
+	}
+	if s.config.Overlay().SmoothProgress {
+		return s.smoothedProgressLocked(time.Now())
+	}
+	return effectiveProgress(s.currentTrack, time.Now())
+}
+
+// effectiveProgress returns the track progress extrapolated to now, clamped to the track duration
+func effectiveProgress(track *TrackInfo, now time.Time) int64 {
+	progress := track.Progress
+	if track.IsPlaying {
+		elapsed := now.Sub(track.UpdatedAt).Milliseconds()
+		if elapsed > 0 {
+			progress += elapsed
+		}
+	}
+	// Spotify occasionally reports progress slightly past the duration at track end
+	if track.Duration > 0 && progress > track.Duration {
+		progress = track.Duration
+	}
+	return progress
+}
+
+// plainLineInfo picks the line of unsynced lyrics matching progress, assuming every line
+// takes an equal share of the track duration
+func plainLineInfo(lines []LyricsLine, track *TrackInfo, progress int64) *DisplayInfo {
+	lineDuration := track.Duration / int64(len(lines))
+	if lineDuration <= 0 {
+		lineDuration = 1
+	}
+
+	idx := int(progress / lineDuration)
+	if idx >= len(lines) {
+		idx = len(lines) - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+
+	nextLine, nextIsSection := "", false
+	if idx+1 < len(lines) {
+		nextLine = lines[idx+1].Text
+		nextIsSection = lines[idx+1].IsSection
+	}
+
+	lineStartTime := int64(idx) * lineDuration
+	lineProgress := progress - lineStartTime
+	if lineProgress > lineDuration {
+		lineProgress = lineDuration
+	}
+
+	return &DisplayInfo{
+		CurrentLine:      lines[idx].Text,
+		CurrentIsSection: lines[idx].IsSection,
+		NextLine:         nextLine,
+		NextIsSection:    nextIsSection,
+		IsPlaying:        track.IsPlaying,
+		LineDuration:     lineDuration,
+		LineProgress:     lineProgress,
+		LineStartTime:    lineStartTime,
+		EstimatedLineMs:  lineDuration,
+	}
+}
+
+// withoutSections returns the lines minus section headers, reusing the slice when there are none
+func withoutSections(lines []LyricsLine) []LyricsLine {
+	for i, line := range lines {
+		if !line.IsSection {
+			continue
+		}
+		filtered := append(make([]LyricsLine, 0, len(lines)), lines[:i]...)
+		for _, rest := range lines[i+1:] {
+			if !rest.IsSection {
+				filtered = append(filtered, rest)
+			}
+		}
+		return filtered
+	}
+	return lines
+}
+
+// findCurrentAndNext returns the index of the line being sung at progress and of the next
+// non-empty line (-1 when none). With skipEmpty, an empty current line (an instrumental gap)
+// is replaced by the upcoming non-empty line.
+func findCurrentAndNext(lines []LyricsLine, progress int64, skipEmpty bool) (int, int) {
+	current := -1
+	for i, line := range lines {
+		if line.Timestamp > progress {
+			break
+		}
+		current = i
+	}
+	if current < 0 {
+		return -1, -1
+	}
+
+	if skipEmpty && lines[current].Text == "" {
+		for j := current + 1; j < len(lines); j++ {
+			if lines[j].Text != "" {
+				current = j
+				break
+			}
+		}
+	}
+
+	for j := current + 1; j < len(lines); j++ {
+		if lines[j].Text != "" {
+			return current, j
+		}
+	}
+	return current, -1
+}
+
+// distinctNextLine returns the index of the first non-empty line from index from onwards whose
+// text differs from text, so back-to-back repeats don't preview the line already shown, or -1
+func distinctNextLine(lines []LyricsLine, from int, text string) int {
+	for j := from; j < len(lines); j++ {
+		if lines[j].Text != "" && lines[j].Text != text {
+			return j
+		}
+	}
+	return -1
+}
+
+// wordProgress returns how far (0-1) the singer is through the line, using word timings when
+// available and falling back to the linear lineProgress/lineDuration estimate
+func wordProgress(words []LyricsWord, progress, lineEnd, lineProgress, lineDuration int64) float64 {
+	if len(words) == 0 {
+		if lineDuration <= 0 {
+			return 0
+		}
+		return float64(lineProgress) / float64(lineDuration)
+	}
+	if progress < words[0].Timestamp {
+		return 0
+	}
+
+	// Each word gets an equal share of the line; interpolate within the word being sung
+	for i, word := range words {
+		wordEnd := lineEnd
+		if i+1 < len(words) {
+			wordEnd = words[i+1].Timestamp
+		}
+		if progress >= wordEnd {
+			continue
+		}
+		within := 0.0
+		if wordEnd > word.Timestamp {
+			within = float64(progress-word.Timestamp) / float64(wordEnd-word.Timestamp)
+		}
+		return (float64(i) + within) / float64(len(words))
+	}
+	return 1
+}
+
+// wordTimings spans each timed word from its timestamp to the next word's (or its closing tag,
+// or lineEnd for the last word). Lines without word timings are a single word spanning the line.
+func wordTimings(text string, words []LyricsWord, lineStart, lineEnd int64) []WordTiming {
+	if len(words) == 0 {
+		return []WordTiming{{Text: text, StartMs: lineStart, EndMs: lineEnd}}
+	}
+
+	timings := make([]WordTiming, len(words))
+	for i, word := range words {
+		end := lineEnd
+		if i+1 < len(words) {
+			end = words[i+1].Timestamp
+		} else if word.End > word.Timestamp {
+			end = word.End
+		}
+		timings[i] = WordTiming{Text: word.Text, StartMs: word.Timestamp, EndMs: max(end, word.Timestamp)}
+	}
+	return timings
+}
+
+// highlightIndex returns how many runes of text to highlight, always ending on a word boundary:
+// through the last word that has started when word timings are available (and can be found in
+// text), otherwise the whole words covered by the linear lineProgress/lineDuration estimate
+func highlightIndex(text string, words []LyricsWord, progress, lineProgress, lineDuration int64) int {
+	if end, ok := wordHighlightEnd(text, words, progress); ok {
+		return utf8.RuneCountInString(text[:end])
+	}
+
+	runes := []rune(text)
+	if lineDuration <= 0 || lineProgress >= lineDuration {
+		return len(runes)
+	}
+	cutoff := int(int64(len(runes)) * max(lineProgress, 0) / lineDuration)
+
+	// Back off to the end of the last word that fits before the cutoff
+	boundary := 0
+	for i := 1; i <= cutoff; i++ {
+		if !unicode.IsSpace(runes[i-1]) && (i == len(runes) || unicode.IsSpace(runes[i])) {
+			boundary = i
+		}
+	}
+	return boundary
+}
+
+// wordHighlightEnd returns the byte offset in text just past the last word that has started by
+// progress, or false when there are no word timings or they don't line up with text
+func wordHighlightEnd(text string, words []LyricsWord, progress int64) (int, bool) {
+	if len(words) == 0 {
+		return 0, false
+	}
+	end := 0
+	pos := 0
+	for _, word := range words {
+		if word.Timestamp > progress {
+			break
+		}
+		w := strings.TrimSpace(word.Text)
+		idx := strings.Index(text[pos:], w)
+		if idx < 0 {
+			return 0, false
+		}
+		pos += idx + len(w)
+		end = pos
+	}
+	return end, true
+}
+
+// finalLineInfo returns display info holding the last non-empty line as fully sung
+func finalLineInfo(lines []LyricsLine, isPlaying bool) *DisplayInfo {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i].Text == "" {
+			continue
+		}
+		lineDuration := int64(3000) // Default 3 seconds
+		return &DisplayInfo{
+			CurrentLine:      lines[i].Text,
+			CurrentSecondary: lines[i].SecondaryText,
+			NextLine:         "",
+			IsPlaying:        isPlaying,
+			LineDuration:     lineDuration,
+			LineProgress:     lineDuration,
+			HighlightIndex:   utf8.RuneCountInString(lines[i].Text),
+			LineStartTime:    lines[i].Timestamp,
+		}
+	}
+	return &DisplayInfo{
+		CurrentLine: "",
+		NextLine:    "",
+		IsPlaying:   isPlaying,
+	}
+}
+
 // DisplayInfo holds the information to display in the overlay
 type DisplayInfo struct {
 	CurrentLine   string `json:"current_line"`
@@ -217,6 +892,41 @@ type DisplayInfo struct {
 	LineDuration  int64  `json:"line_duration_ms"`   // Duration of current line in ms
 	LineProgress  int64  `json:"line_progress_ms"`   // Progress into current line in ms
 	LineStartTime int64  `json:"line_start_time_ms"` // Timestamp when current line started
+	ReadOnly      bool   `json:"read_only"`          // Showing lyrics from history, not playback
+
+	CurrentSecondary string `json:"current_secondary,omitempty"` // Translation of the current line, if any
+	CurrentIsSection bool   `json:"current_is_section"`          // Current line is a section header like "[Chorus]"
+	NextIsSection    bool   `json:"next_is_section"`
+
+	Source          string  `json:"source,omitempty"`     // Provider and variant shown, e.g. "LRCLIB (synced)"
+	MatchConfidence float64 `json:"match_confidence"`     // 0-1 confidence the lyrics match the track
+	MatchKind       string  `json:"match_kind,omitempty"` // exact, fuzzy or fallback when the provider reports it
+	MatchScore      int     `json:"match_score"`
+	Visible         bool    `json:"visible"`         // Whether the overlay should currently be shown
+	WordProgress    float64 `json:"word_progress"`   // 0-1 fill of the current line, from word timings when available
+	HighlightIndex  int     `json:"highlight_index"` // Runes of the current synced line to highlight, on a word boundary
+	Frozen          bool    `json:"frozen"`          // Lyrics are held on a line while playback continues
+	Lingering       bool    `json:"lingering"`       // Showing the last line briefly after playback stopped
+
+	// Timed words of the current synced line for per-word fades; one word spanning the line
+	// without word timings (or with Overlay.WordTiming off)
+	Words []WordTiming `json:"words,omitempty"`
+
+	// A low-confidence match is waiting for the user to confirm it or pick another candidate
+	NeedsConfirmation bool `json:"needs_confirmation"`
+
+	// Per-line time estimated from the track duration when scrolling unsynced lyrics
+	// (Config.AutoAdvancePlain); 0 when lines have real timestamps
+	EstimatedLineMs int64 `json:"estimated_line_ms"`
+
+	// Waiting for the first synced line; NextLine previews it
+	Intro            bool  `json:"intro"`
+	IntroRemainingMs int64 `json:"intro_remaining_ms"` // Countdown to the first line
+
+	// Track position, only filled when Overlay.ShowProgress is enabled
+	ProgressText    string  `json:"progress_text,omitempty"` // e.g. "1:23"
+	DurationText    string  `json:"duration_text,omitempty"` // e.g. "3:45"
+	ProgressPercent float64 `json:"progress_percent"`        // 0-100
 }
 
 // ToggleVisibility toggles the overlay visibility
@@ -224,12 +934,14 @@ func (s *Service) ToggleVisibility() bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.isVisible = !s.isVisible
+	// Toggle what the user sees: showing an auto-hidden overlay overrides the auto-hide
+	s.isVisible = !s.isVisibleLocked()
+	s.autoHidden = make(map[string]bool)
 
 	// Update config
-	cfg := s.config.Get()
-	cfg.Overlay.Visible = s.isVisible
-	_ = s.config.UpdateOverlay(cfg.Overlay)
+	overlayConfig := s.config.Overlay()
+	overlayConfig.Visible = s.isVisible
+	_ = s.config.UpdateOverlay(overlayConfig)
 
 	return s.isVisible
 }
@@ -238,7 +950,12 @@ func (s *Service) ToggleVisibility() bool {
 func (s *Service) IsVisible() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.isVisible
+	return s.isVisibleLocked()
+}
+
+// isVisibleLocked returns whether the overlay is shown, honoring auto-hide (must hold lock)
+func (s *Service) isVisibleLocked() bool {
+	return s.isVisible && len(s.autoHidden) == 0
 }
 
 // SetVisibility sets the overlay visibility
@@ -247,21 +964,45 @@ func (s *Service) SetVisibility(visible bool) {
 	defer s.mu.Unlock()
 
 	s.isVisible = visible
+	s.autoHidden = make(map[string]bool)
 
 	// Update config
-	cfg := s.config.Get()
-	cfg.Overlay.Visible = visible
-	_ = s.config.UpdateOverlay(cfg.Overlay)
+	overlayConfig := s.config.Overlay()
+	overlayConfig.Visible = visible
+	_ = s.config.UpdateOverlay(overlayConfig)
+}
+
+// SetAutoHidden hides or restores the overlay for the given reason without touching the
+// user's saved visibility preference
+func (s *Service) SetAutoHidden(reason string, hidden bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setAutoHiddenLocked(reason, hidden)
+}
+
+// setAutoHiddenLocked records an auto-hide reason (must hold write lock)
+func (s *Service) setAutoHiddenLocked(reason string, hidden bool) {
+	if hidden {
+		s.autoHidden[reason] = true
+	} else {
+		delete(s.autoHidden, reason)
+	}
 }
 
 // GetOverlayConfig returns current overlay configuration
 func (s *Service) GetOverlayConfig() config.OverlayConfig {
-	return s.config.Get().Overlay
+	return s.config.Overlay()
 }
 
 // UpdateOverlayConfig updates overlay configuration
+// The change applies immediately; the disk write is debounced (see CommitOverlayConfig).
 func (s *Service) UpdateOverlayConfig(overlayConfig config.OverlayConfig) error {
-	return s.config.UpdateOverlay(overlayConfig)
+	return s.config.SetOverlay(overlayConfig)
+}
+
+// CommitOverlayConfig writes pending overlay changes to disk now
+func (s *Service) CommitOverlayConfig() error {
+	return s.config.Flush()
 }
 
 // Shutdown performs cleanup
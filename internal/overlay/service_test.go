@@ -0,0 +1,538 @@
+package overlay
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"lyrics-overlay/internal/config"
+)
+
+// newTestService creates an overlay service backed by a temp config file
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	configSvc, err := config.NewWithPath(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("config.NewWithPath failed: %v", err)
+	}
+
+	service, err := New(configSvc)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return service
+}
+
+func syncedTestLyrics() *LyricsData {
+	return &LyricsData{
+		Source:   "Test",
+		IsSynced: true,
+		Lines: []LyricsLine{
+			{Text: "First line", Timestamp: 10000},
+			{Text: "Second line", Timestamp: 100000},
+			{Text: "Last line", Timestamp: 190000},
+			{Text: "", Timestamp: 198000},
+		},
+	}
+}
+
+func TestGetDisplayInfo_ProgressNearEnd(t *testing.T) {
+	tests := []struct {
+		name      string
+		progress  int64
+		updatedAt time.Duration
+		wantLine  string
+		wantNext  string
+	}{
+		{"mid song", 100500, 0, "Second line", "Last line"},
+		{"within end window", 199500, 0, "Last line", ""},
+		{"progress beyond duration", 200800, 0, "Last line", ""},
+		{"extrapolated past duration", 199000, 5 * time.Second, "Last line", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestService(t)
+			s.SetCurrentLyrics(syncedTestLyrics())
+			s.SetCurrentTrack(&TrackInfo{
+				ID:        "track",
+				Duration:  200000,
+				Progress:  tc.progress,
+				IsPlaying: true,
+				UpdatedAt: time.Now().Add(-tc.updatedAt),
+			})
+
+			info := s.GetDisplayInfo()
+			if info.CurrentLine != tc.wantLine {
+				t.Errorf("CurrentLine = %q; want %q", info.CurrentLine, tc.wantLine)
+			}
+			if info.NextLine != tc.wantNext {
+				t.Errorf("NextLine = %q; want %q", info.NextLine, tc.wantNext)
+			}
+		})
+	}
+}
+
+func TestGetDisplayInfo_TrackSyncOffset(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(syncedTestLyrics())
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 99000, UpdatedAt: time.Now()})
+
+	// The global lead (350ms) isn't enough to reach the second line
+	if info := s.GetDisplayInfo(); info.CurrentLine != "First line" {
+		t.Fatalf("CurrentLine = %q; want %q with the global offset", info.CurrentLine, "First line")
+	}
+
+	if err := s.config.SetTrackSyncOffset("track", 1500); err != nil {
+		t.Fatalf("SetTrackSyncOffset failed: %v", err)
+	}
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Second line" {
+		t.Errorf("CurrentLine = %q; want %q with the track offset", info.CurrentLine, "Second line")
+	}
+}
+
+func TestEffectiveProgress_ClampsToDuration(t *testing.T) {
+	now := time.Now()
+	track := &TrackInfo{
+		Duration:  180000,
+		Progress:  179000,
+		IsPlaying: true,
+		UpdatedAt: now.Add(-3 * time.Second),
+	}
+
+	if got := effectiveProgress(track, now); got != 180000 {
+		t.Errorf("effectiveProgress = %d; want 180000", got)
+	}
+
+	track.IsPlaying = false
+	if got := effectiveProgress(track, now); got != 179000 {
+		t.Errorf("effectiveProgress (paused) = %d; want 179000", got)
+	}
+}
+
+func TestGetDisplayInfo_ReadOnlyLyrics(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(syncedTestLyrics())
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 100500, IsPlaying: true, UpdatedAt: time.Now()})
+
+	s.ShowReadOnlyLyrics(&LyricsData{
+		Source: "Test",
+		Lines:  []LyricsLine{{Text: "Old song"}, {Text: "Second verse"}},
+	})
+
+	info := s.GetDisplayInfo()
+	if !info.ReadOnly {
+		t.Error("Expected ReadOnly to be set")
+	}
+	if info.CurrentLine != "Old song" || info.NextLine != "Second verse" {
+		t.Errorf("Unexpected lines: %q / %q", info.CurrentLine, info.NextLine)
+	}
+
+	s.ClearReadOnlyLyrics()
+	if info := s.GetDisplayInfo(); info.ReadOnly || info.CurrentLine != "Second line" {
+		t.Errorf("Expected playback display after clearing, got %q (read-only %v)", info.CurrentLine, info.ReadOnly)
+	}
+}
+
+func TestSetCurrentLyrics_HideWhenNoLyrics(t *testing.T) {
+	s := newTestService(t)
+	s.config.Get().HideWhenNoLyrics = true
+
+	s.SetCurrentLyrics(&LyricsData{Source: "LRCLIB", IsInstrumental: true})
+	if s.IsVisible() {
+		t.Error("Expected overlay to auto-hide for an instrumental")
+	}
+	if !s.config.Get().Overlay.Visible {
+		t.Error("Auto-hide should not change the saved visibility preference")
+	}
+
+	s.SetCurrentLyrics(syncedTestLyrics())
+	if !s.IsVisible() {
+		t.Error("Expected overlay to reappear when lyrics are available")
+	}
+
+	s.SetCurrentLyrics(nil)
+	if s.IsVisible() {
+		t.Error("Expected overlay to auto-hide when no lyrics were found")
+	}
+
+	// A manual show wins over the auto-hide
+	if !s.ToggleVisibility() || !s.IsVisible() {
+		t.Error("Expected manual toggle to show the auto-hidden overlay")
+	}
+}
+
+func TestSetCurrentLyrics_AutoHideDisabled(t *testing.T) {
+	s := newTestService(t)
+
+	s.SetCurrentLyrics(nil)
+	if !s.IsVisible() {
+		t.Error("Expected overlay to stay visible when auto-hide is disabled")
+	}
+}
+
+func TestGetDisplayInfo_AutoAdvancePlain(t *testing.T) {
+	plain := &LyricsData{
+		Source: "Test",
+		Lines:  []LyricsLine{{Text: "One"}, {Text: "Two"}, {Text: "Three"}, {Text: "Four"}},
+	}
+	track := &TrackInfo{ID: "track", Duration: 200000, Progress: 120000, UpdatedAt: time.Now()}
+
+	s := newTestService(t)
+	s.SetCurrentLyrics(plain)
+	s.SetCurrentTrack(track)
+	if info := s.GetDisplayInfo(); info.CurrentLine != "One" {
+		t.Errorf("CurrentLine without auto-advance = %q; want %q", info.CurrentLine, "One")
+	}
+
+	s.config.Get().AutoAdvancePlain = true
+	info := s.GetDisplayInfo()
+	if info.CurrentLine != "Three" || info.NextLine != "Four" {
+		t.Errorf("Lines = %q / %q; want %q / %q", info.CurrentLine, info.NextLine, "Three", "Four")
+	}
+	if info.LineDuration != 50000 || info.LineProgress != 20000 {
+		t.Errorf("Line timing = %d/%d; want 20000/50000", info.LineProgress, info.LineDuration)
+	}
+	if info.EstimatedLineMs != 50000 {
+		t.Errorf("EstimatedLineMs = %d; want 50000", info.EstimatedLineMs)
+	}
+}
+
+func TestGetDisplayInfo_ShowProgress(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(syncedTestLyrics())
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 225000, Progress: 83000, UpdatedAt: time.Now()})
+
+	if info := s.GetDisplayInfo(); info.ProgressText != "" {
+		t.Errorf("ProgressText = %q while disabled; want empty", info.ProgressText)
+	}
+
+	s.config.Get().Overlay.ShowProgress = true
+	info := s.GetDisplayInfo()
+	if info.ProgressText != "1:23" || info.DurationText != "3:45" {
+		t.Errorf("Progress = %s / %s; want 1:23 / 3:45", info.ProgressText, info.DurationText)
+	}
+	if info.ProgressPercent < 36.8 || info.ProgressPercent > 36.9 {
+		t.Errorf("ProgressPercent = %f; want ~36.89", info.ProgressPercent)
+	}
+}
+
+func TestWordProgress(t *testing.T) {
+	words := []LyricsWord{{Text: "Hello", Timestamp: 10000}, {Text: "big", Timestamp: 10500}, {Text: "world", Timestamp: 11000}}
+
+	tests := []struct {
+		name     string
+		words    []LyricsWord
+		progress int64
+		want     float64
+	}{
+		{"before first word", words, 9900, 0},
+		{"halfway through first word", words, 10250, 0.5 / 3},
+		{"start of last word", words, 11000, 2.0 / 3},
+		{"line finished", words, 14000, 1},
+		{"linear fallback", nil, 11000, 0.25},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Line runs 10000-14000 with linear progress 1000/4000
+			got := wordProgress(tc.words, tc.progress, 14000, 1000, 4000)
+			if got < tc.want-1e-9 || got > tc.want+1e-9 {
+				t.Errorf("wordProgress = %f; want %f", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHighlightIndex(t *testing.T) {
+	text := "Hello big world"
+	words := []LyricsWord{{Text: "Hello ", Timestamp: 10000}, {Text: "big ", Timestamp: 10500}, {Text: "world", Timestamp: 11000}}
+
+	tests := []struct {
+		name         string
+		words        []LyricsWord
+		progress     int64
+		lineProgress int64
+		want         int
+	}{
+		{"before first word", words, 9900, 0, 0},
+		{"first word started", words, 10100, 100, 5},
+		{"second word started", words, 10600, 600, 9},
+		{"last word started", words, 11000, 1000, 15},
+		{"linear snaps back to a word end", nil, 11000, 2000, 5},
+		{"linear full line", nil, 14000, 4000, 15},
+		{"unmatched words fall back to linear", []LyricsWord{{Text: "Hola", Timestamp: 10000}}, 11000, 2000, 5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Line runs 10000-14000
+			if got := highlightIndex(text, tc.words, tc.progress, tc.lineProgress, 4000); got != tc.want {
+				t.Errorf("highlightIndex = %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSetLyricsForTrack_NeedsConfirmation(t *testing.T) {
+	s := newTestService(t)
+	s.config.Get().AutoApplyConfidence = 0.6
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 20000, IsPlaying: true, UpdatedAt: time.Now()})
+
+	// A confident match applies as usual
+	confident := syncedTestLyrics()
+	confident.MatchConfidence = 0.9
+	s.SetLyricsForTrack("track", confident)
+	if s.GetCurrentLyrics() != confident || s.GetDisplayInfo().NeedsConfirmation {
+		t.Fatal("Expected a confident match to apply without confirmation")
+	}
+
+	// A weak one is held back until confirmed
+	weak := syncedTestLyrics()
+	weak.MatchConfidence = 0.3
+	if !s.SetLyricsForTrack("track", weak) {
+		t.Fatal("SetLyricsForTrack rejected lyrics for the current track")
+	}
+	if s.GetCurrentLyrics() != nil || s.PendingLyrics() != weak {
+		t.Fatal("Expected the weak match to be pending, not shown")
+	}
+	if info := s.GetDisplayInfo(); !info.NeedsConfirmation || !strings.Contains(info.CurrentLine, "confirm") {
+		t.Errorf("Display = %q (needs confirmation %v); want a confirmation prompt", info.CurrentLine, info.NeedsConfirmation)
+	}
+	if !s.ConfirmLyrics() || s.GetCurrentLyrics() != weak || s.PendingLyrics() != nil {
+		t.Error("Expected ConfirmLyrics to apply the pending match")
+	}
+
+	// A track change drops an unconfirmed match
+	s.SetLyricsForTrack("track", weak)
+	s.SetCurrentTrack(&TrackInfo{ID: "other", Duration: 200000, IsPlaying: true, UpdatedAt: time.Now()})
+	if s.PendingLyrics() != nil || s.ConfirmLyrics() {
+		t.Error("Expected the pending match to be dropped on track change")
+	}
+}
+
+func TestFreezeDisplay(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(syncedTestLyrics())
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 20000, IsPlaying: true, UpdatedAt: time.Now()})
+
+	if !s.FreezeDisplay() {
+		t.Fatal("FreezeDisplay returned false with a track playing")
+	}
+
+	// Playback moves on, but the frozen line stays
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 120000, IsPlaying: true, UpdatedAt: time.Now()})
+	info := s.GetDisplayInfo()
+	if info.CurrentLine != "First line" || !info.Frozen {
+		t.Errorf("Frozen display = %q (frozen %v); want %q", info.CurrentLine, info.Frozen, "First line")
+	}
+
+	s.UnfreezeDisplay()
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Second line" || info.Frozen {
+		t.Errorf("Unfrozen display = %q (frozen %v); want %q", info.CurrentLine, info.Frozen, "Second line")
+	}
+
+	// A track change releases the freeze
+	s.FreezeDisplay()
+	s.SetCurrentTrack(&TrackInfo{ID: "other", Duration: 200000, Progress: 0, UpdatedAt: time.Now()})
+	if s.IsFrozen() {
+		t.Error("Expected track change to unfreeze the display")
+	}
+}
+
+func TestFindCurrentAndNext(t *testing.T) {
+	lines := []LyricsLine{
+		{Text: "One", Timestamp: 1000},
+		{Text: "", Timestamp: 5000},
+		{Text: "", Timestamp: 6000},
+		{Text: "Two", Timestamp: 20000},
+		{Text: "Three", Timestamp: 25000},
+		{Text: "", Timestamp: 30000},
+	}
+
+	tests := []struct {
+		name        string
+		progress    int64
+		skipEmpty   bool
+		wantCurrent int
+		wantNext    int
+	}{
+		{"before first line", 500, true, -1, -1},
+		{"exactly on first line", 1000, true, 0, 3},
+		{"first line", 3000, true, 0, 3},
+		{"gap skipped to upcoming line", 7000, true, 3, 4},
+		{"gap shown as blank", 7000, false, 2, 3},
+		{"mid line", 22000, true, 3, 4},
+		{"last non-empty line", 26000, true, 4, -1},
+		{"trailing empty line with nothing after", 31000, true, 5, -1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			current, next := findCurrentAndNext(lines, tc.progress, tc.skipEmpty)
+			if current != tc.wantCurrent || next != tc.wantNext {
+				t.Errorf("findCurrentAndNext(%d) = %d, %d; want %d, %d", tc.progress, current, next, tc.wantCurrent, tc.wantNext)
+			}
+		})
+	}
+}
+
+func TestGetDisplayInfo_ShowEmptyLines(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(&LyricsData{
+		Source:   "Test",
+		IsSynced: true,
+		Lines:    []LyricsLine{{Text: "One", Timestamp: 1000}, {Text: "", Timestamp: 5000}, {Text: "Two", Timestamp: 20000}},
+	})
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 8000, UpdatedAt: time.Now()})
+
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Two" {
+		t.Errorf("CurrentLine = %q; want upcoming line %q by default", info.CurrentLine, "Two")
+	}
+
+	s.config.Get().Overlay.ShowEmptyLines = true
+	if info := s.GetDisplayInfo(); info.CurrentLine != "" || info.NextLine != "Two" {
+		t.Errorf("Lines = %q / %q; want blank / %q", info.CurrentLine, info.NextLine, "Two")
+	}
+}
+
+func TestSetLyricsForTrack_IgnoresStaleTrack(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentTrack(&TrackInfo{ID: "new", UpdatedAt: time.Now()})
+
+	if s.SetLyricsForTrack("old", syncedTestLyrics()) {
+		t.Error("Expected lyrics for a previous track to be rejected")
+	}
+	if s.GetCurrentLyrics() != nil {
+		t.Error("Stale lyrics should not be applied")
+	}
+
+	if !s.SetLyricsForTrack("new", syncedTestLyrics()) || s.GetCurrentLyrics() == nil {
+		t.Error("Expected lyrics for the current track to be applied")
+	}
+}
+
+func TestGetDisplayInfo_SectionHeaders(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(&LyricsData{
+		Source: "Test",
+		Lines:  []LyricsLine{{Text: "[Chorus]", IsSection: true}, {Text: "Sing along"}, {Text: "Again"}},
+	})
+	s.SetCurrentTrack(&TrackInfo{ID: "track", UpdatedAt: time.Now()})
+
+	info := s.GetDisplayInfo()
+	if info.CurrentLine != "[Chorus]" || !info.CurrentIsSection || info.NextIsSection {
+		t.Errorf("Display = %q (section %v, next section %v); want tagged header", info.CurrentLine, info.CurrentIsSection, info.NextIsSection)
+	}
+
+	s.config.Get().HideSectionHeaders = true
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Sing along" || info.NextLine != "Again" {
+		t.Errorf("Lines with headers hidden = %q / %q; want %q / %q", info.CurrentLine, info.NextLine, "Sing along", "Again")
+	}
+}
+
+func TestEndTrack_Linger(t *testing.T) {
+	s := newTestService(t)
+	s.config.Get().Overlay.LingerSeconds = 5
+	s.SetCurrentLyrics(syncedTestLyrics())
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 199500, IsPlaying: true, UpdatedAt: time.Now()})
+
+	s.EndTrack()
+	info := s.GetDisplayInfo()
+	if info.CurrentLine != "Last line" || !info.Lingering || info.IsPlaying {
+		t.Errorf("Lingering display = %q (lingering %v, playing %v); want %q", info.CurrentLine, info.Lingering, info.IsPlaying, "Last line")
+	}
+
+	// Once the linger expires the overlay clears
+	s.mu.Lock()
+	s.lingerUntil = time.Now().Add(-time.Second)
+	s.mu.Unlock()
+	if info := s.GetDisplayInfo(); info.CurrentLine != "No track playing" || info.Lingering {
+		t.Errorf("Display after linger = %q; want %q", info.CurrentLine, "No track playing")
+	}
+}
+
+func TestEndTrack_NoLinger(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(syncedTestLyrics())
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 20000, IsPlaying: true, UpdatedAt: time.Now()})
+
+	s.EndTrack()
+	if info := s.GetDisplayInfo(); info.CurrentLine != "No track playing" {
+		t.Errorf("Display after EndTrack = %q; want %q", info.CurrentLine, "No track playing")
+	}
+}
+
+func TestGetDisplayInfo_RepeatedLinePreview(t *testing.T) {
+	// Parsed from a repeated chorus:
+	//   [00:10.00]Na na na
+	//   [00:12.00]Na na na
+	//   [00:14.00]Hey
+	s := newTestService(t)
+	s.SetCurrentLyrics(&LyricsData{
+		Source:   "Test",
+		IsSynced: true,
+		Lines: []LyricsLine{
+			{Text: "Na na na", Timestamp: 10000},
+			{Text: "Na na na", Timestamp: 12000},
+			{Text: "Hey", Timestamp: 14000},
+		},
+	})
+	// With the default sync lead added, progress lands half a second into the first line
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 10500 - defaultSyncLeadMs, UpdatedAt: time.Now()})
+
+	info := s.GetDisplayInfo()
+	if info.CurrentLine != "Na na na" || info.NextLine != "Hey" {
+		t.Errorf("Display = %q / %q; want %q / %q", info.CurrentLine, info.NextLine, "Na na na", "Hey")
+	}
+	// Timing still advances to the repeated line
+	if info.LineDuration != 2000 {
+		t.Errorf("LineDuration = %d; want 2000", info.LineDuration)
+	}
+}
+
+func TestShareText(t *testing.T) {
+	s := newTestService(t)
+	if _, err := s.ShareText(); err == nil {
+		t.Error("Expected an error with no track playing")
+	}
+
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Name: "Song", Artists: []string{"A", "B"}, Duration: 200000, Progress: 20000, UpdatedAt: time.Now()})
+	if text, err := s.ShareText(); err != nil || text != "🎵 Song — A, B" {
+		t.Errorf("ShareText without lyrics = %q, %v; want track only", text, err)
+	}
+
+	s.SetCurrentLyrics(syncedTestLyrics())
+	if text, err := s.ShareText(); err != nil || text != "🎵 Song — A, B\n> First line" {
+		t.Errorf("ShareText = %q, %v; want track and current line", text, err)
+	}
+}
+
+func TestGetDisplayInfo_LongIntro(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(&LyricsData{
+		Source:   "Test",
+		IsSynced: true,
+		Lines: []LyricsLine{
+			{Text: "First line", Timestamp: 45000},
+			{Text: "Second line", Timestamp: 50000},
+		},
+	})
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 20000, UpdatedAt: time.Now()})
+
+	info := s.GetDisplayInfo()
+	if !info.Intro || info.CurrentLine != introMarker || info.NextLine != "First line" {
+		t.Fatalf("Display = %+v; want the intro state previewing the first line", info)
+	}
+	// Default lead of 350ms is applied before counting down
+	if want := int64(45000 - 20000 - 350); info.IntroRemainingMs != want {
+		t.Errorf("IntroRemainingMs = %d; want %d", info.IntroRemainingMs, want)
+	}
+
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 46000, UpdatedAt: time.Now()})
+	if info := s.GetDisplayInfo(); info.Intro || info.CurrentLine != "First line" {
+		t.Errorf("Display = %+v; want the first line once it starts", info)
+	}
+}
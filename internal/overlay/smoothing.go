@@ -0,0 +1,71 @@
+package overlay
+
+import "time"
+
+const (
+	// defaultPollWindow is the expected time between playback polls until SetPollInterval is called
+	defaultPollWindow = 5 * time.Second
+	// extrapolationMarginMs is how far past the expected next poll progress may still extrapolate
+	extrapolationMarginMs int64 = 1000
+	// maxHoldBackMs is the largest backwards correction held instead of shown; larger jumps are seeks
+	maxHoldBackMs int64 = 1500
+)
+
+// progressFloor is the last smoothed progress shown for a track on a device
+type progressFloor struct {
+	trackID  string
+	deviceID string
+	progress int64
+}
+
+// smoothProgress extrapolates progress like effectiveProgress, but never further than the
+// poll window plus a margin past the last update, and never back below floor by less than
+// maxHoldBackMs, so a poll correcting a small overshoot doesn't flip lines back and forth.
+// A negative floor disables the hold.
+func smoothProgress(track *TrackInfo, now time.Time, window time.Duration, floor int64) int64 {
+	progress := track.Progress
+	if track.IsPlaying {
+		elapsed := now.Sub(track.UpdatedAt).Milliseconds()
+		if maxElapsed := window.Milliseconds() + extrapolationMarginMs; elapsed > maxElapsed {
+			elapsed = maxElapsed
+		}
+		if elapsed > 0 {
+			progress += elapsed
+		}
+		if floor > progress && floor-progress <= maxHoldBackMs {
+			progress = floor
+		}
+	}
+	if track.Duration > 0 && progress > track.Duration {
+		progress = track.Duration
+	}
+	return progress
+}
+
+// SetPollInterval tells the overlay how often playback is polled, bounding extrapolation
+func (s *Service) SetPollInterval(interval time.Duration) {
+	s.smoothMu.Lock()
+	defer s.smoothMu.Unlock()
+	s.pollWindow = interval
+}
+
+// smoothedProgressLocked returns smoothed progress for the current track and records it as the
+// new floor (must hold read lock). A different track or device starts from scratch, like a seek.
+func (s *Service) smoothedProgressLocked(now time.Time) int64 {
+	s.smoothMu.Lock()
+	defer s.smoothMu.Unlock()
+
+	track := s.currentTrack
+	window := s.pollWindow
+	if window <= 0 {
+		window = defaultPollWindow
+	}
+	floor := int64(-1)
+	if s.floor.trackID == track.ID && s.floor.deviceID == track.DeviceID {
+		floor = s.floor.progress
+	}
+
+	progress := smoothProgress(track, now, window, floor)
+	s.floor = progressFloor{trackID: track.ID, deviceID: track.DeviceID, progress: progress}
+	return progress
+}
@@ -0,0 +1,58 @@
+package overlay
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSmoothProgress(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		track  TrackInfo
+		window time.Duration
+		floor  int64
+		want   int64
+	}{
+		{"normal extrapolation", TrackInfo{Progress: 10000, Duration: 200000, IsPlaying: true, UpdatedAt: now.Add(-2 * time.Second)}, 5 * time.Second, -1, 12000},
+		{"overshoot clamped to window", TrackInfo{Progress: 10000, Duration: 200000, IsPlaying: true, UpdatedAt: now.Add(-20 * time.Second)}, 5 * time.Second, -1, 16000},
+		{"small regression held", TrackInfo{Progress: 10000, Duration: 200000, IsPlaying: true, UpdatedAt: now}, 5 * time.Second, 10800, 10800},
+		{"seek backwards accepted", TrackInfo{Progress: 10000, Duration: 200000, IsPlaying: true, UpdatedAt: now}, 5 * time.Second, 60000, 10000},
+		{"paused ignores floor", TrackInfo{Progress: 10000, Duration: 200000, IsPlaying: false, UpdatedAt: now.Add(-time.Minute)}, 5 * time.Second, 10800, 10000},
+		{"clamped to duration", TrackInfo{Progress: 199000, Duration: 200000, IsPlaying: true, UpdatedAt: now.Add(-3 * time.Second)}, 5 * time.Second, -1, 200000},
+	}
+
+	for _, tt := range tests {
+		track := tt.track
+		if got := smoothProgress(&track, now, tt.window, tt.floor); got != tt.want {
+			t.Errorf("%s: smoothProgress = %d; want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetDisplayInfo_SmoothingHoldsLineAfterOvershoot(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(syncedTestLyrics())
+
+	// Extrapolation crossed into the second line just before the poll...
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 98900, IsPlaying: true, UpdatedAt: time.Now().Add(-time.Second)})
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Second line" {
+		t.Fatalf("CurrentLine = %q; want %q", info.CurrentLine, "Second line")
+	}
+
+	// ...and the poll reports slightly less; the line must not flip back
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 99200, IsPlaying: true, UpdatedAt: time.Now()})
+	if info := s.GetDisplayInfo(); info.CurrentLine != "Second line" {
+		t.Errorf("CurrentLine after correction = %q; want %q", info.CurrentLine, "Second line")
+	}
+
+	// Without smoothing the correction shows through
+	overlayConfig := s.GetOverlayConfig()
+	overlayConfig.SmoothProgress = false
+	if err := s.UpdateOverlayConfig(overlayConfig); err != nil {
+		t.Fatalf("UpdateOverlayConfig failed: %v", err)
+	}
+	if info := s.GetDisplayInfo(); info.CurrentLine != "First line" {
+		t.Errorf("CurrentLine without smoothing = %q; want %q", info.CurrentLine, "First line")
+	}
+}
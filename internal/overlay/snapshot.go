@@ -0,0 +1,76 @@
+package overlay
+
+import (
+	"strings"
+	"time"
+)
+
+// DisplaySnapshot is a complete, serializable view of the overlay for screen readers and
+// integrations: everything in DisplayInfo plus the track header and the previous line
+type DisplaySnapshot struct {
+	DisplayInfo
+
+	TrackID string `json:"track_id,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Artist  string `json:"artist,omitempty"`
+	Album   string `json:"album,omitempty"`
+	Header  string `json:"header,omitempty"` // "Title — Artist", as a screen reader would announce it
+
+	PreviousLine string `json:"previous_line,omitempty"` // Last sung line before CurrentLine (synced lyrics)
+
+	ProgressMs int64     `json:"progress_ms"` // Track position at CapturedAt
+	DurationMs int64     `json:"duration_ms"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+// GetDisplaySnapshot returns the current display along with the track and surrounding lines
+func (s *Service) GetDisplaySnapshot() *DisplaySnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	snapshot := &DisplaySnapshot{
+		DisplayInfo: *s.currentDisplayInfoLocked(),
+		CapturedAt:  now,
+	}
+	if s.reviewLyrics != nil || s.currentTrack == nil {
+		return snapshot
+	}
+
+	track := s.currentTrack
+	snapshot.TrackID = track.ID
+	snapshot.Title = track.Name
+	snapshot.Artist = strings.Join(track.Artists, ", ")
+	snapshot.Album = track.Album
+	snapshot.Header = track.Name
+	if snapshot.Artist != "" {
+		snapshot.Header += " — " + snapshot.Artist
+	}
+	snapshot.ProgressMs = effectiveProgress(track, now)
+	snapshot.DurationMs = track.Duration
+
+	if s.pendingLyrics == nil && s.currentLyrics != nil && s.currentLyrics.IsSynced && !snapshot.Intro {
+		previous := previousLine(s.currentLyrics.Lines, snapshot.LineStartTime)
+		if s.maskProfanityLocked() {
+			previous = maskProfanity(previous)
+		}
+		snapshot.PreviousLine = TruncateLine(previous, s.config.Overlay().MaxLineChars)
+	}
+	return snapshot
+}
+
+// previousLine returns the last non-empty line before the one starting at lineStart, or ""
+func previousLine(lines []LyricsLine, lineStart int64) string {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i].Timestamp != lineStart {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if lines[j].Text != "" {
+				return lines[j].Text
+			}
+		}
+		return ""
+	}
+	return ""
+}
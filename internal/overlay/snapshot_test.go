@@ -0,0 +1,49 @@
+package overlay
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGetDisplaySnapshot(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(&LyricsData{Source: "Test", IsSynced: true, MatchConfidence: 0.9, Lines: []LyricsLine{
+		{Text: "First", Timestamp: 10000},
+		{Text: "", Timestamp: 20000},
+		{Text: "Third", Timestamp: 30000},
+		{Text: "Fourth", Timestamp: 40000},
+	}})
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Name: "Song", Artists: []string{"Artist", "Guest"}, Album: "Album", Duration: 200000, Progress: 32000, UpdatedAt: time.Now()})
+
+	snapshot := s.GetDisplaySnapshot()
+	if snapshot.Header != "Song — Artist, Guest" || snapshot.Album != "Album" || snapshot.TrackID != "track" {
+		t.Errorf("Header = %q, album %q, track %q; want the playing track", snapshot.Header, snapshot.Album, snapshot.TrackID)
+	}
+	if snapshot.PreviousLine != "First" || snapshot.CurrentLine != "Third" || snapshot.NextLine != "Fourth" {
+		t.Errorf("Lines = %q / %q / %q; want First / Third / Fourth", snapshot.PreviousLine, snapshot.CurrentLine, snapshot.NextLine)
+	}
+	if snapshot.ProgressMs != 32000 || snapshot.DurationMs != 200000 || snapshot.MatchConfidence != 0.9 {
+		t.Errorf("Progress %d/%d, confidence %v; want 32000/200000 and 0.9", snapshot.ProgressMs, snapshot.DurationMs, snapshot.MatchConfidence)
+	}
+
+	// GetDisplayInfo is the snapshot's display part
+	if info := s.GetDisplayInfo(); info.CurrentLine != snapshot.CurrentLine || info.LineProgress != snapshot.LineProgress {
+		t.Errorf("GetDisplayInfo = %+v; want it to match the snapshot", info)
+	}
+
+	// DisplayInfo's fields serialize at the top level, so the snapshot is a superset of it
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	for _, key := range []string{"current_line", "next_line", "line_progress_ms", "previous_line", "header"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("Snapshot JSON is missing %q", key)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package overlay
+
+import (
+	"fmt"
+)
+
+const (
+	// maxSyncSamples is how many recent progress reports the sync estimate averages
+	maxSyncSamples = 10
+	// minSyncSamples is how many reports are needed before suggesting anything
+	minSyncSamples = 3
+	// syncSeekThresholdMs treats larger discrepancies as seeks rather than drift
+	syncSeekThresholdMs int64 = 2000
+	// syncToleranceMs is the drift considered in sync
+	syncToleranceMs int64 = 150
+)
+
+// SyncQuality describes how well extrapolated progress matched Spotify's reports
+type SyncQuality struct {
+	Samples    int    `json:"samples"`
+	DriftMs    int64  `json:"drift_ms"` // Positive: lyrics run early; negative: lyrics run late
+	InSync     bool   `json:"in_sync"`
+	Suggestion string `json:"suggestion"`
+}
+
+// recordSyncSampleLocked compares the progress we extrapolated for the previous report with the
+// newly reported progress (must hold write lock). Pauses, track changes and seeks are skipped.
+func (s *Service) recordSyncSampleLocked(previous, track *TrackInfo) {
+	if previous == nil || track == nil || previous.ID != track.ID {
+		s.syncSamples = nil
+		return
+	}
+	if !previous.IsPlaying || !track.IsPlaying {
+		return
+	}
+
+	drift := effectiveProgress(previous, track.UpdatedAt) - track.Progress
+	if drift > syncSeekThresholdMs || drift < -syncSeekThresholdMs {
+		s.syncSamples = nil // Seek or device switch; start over
+		return
+	}
+	s.syncSamples = append(s.syncSamples, drift)
+	if len(s.syncSamples) > maxSyncSamples {
+		s.syncSamples = s.syncSamples[len(s.syncSamples)-maxSyncSamples:]
+	}
+}
+
+// GetSyncQuality estimates whether lyrics run early or late from recent playback and suggests
+// how to adjust the sync offset
+func (s *Service) GetSyncQuality() SyncQuality {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	quality := SyncQuality{Samples: len(s.syncSamples)}
+	if quality.Samples < minSyncSamples {
+		quality.Suggestion = "Collecting timing data, keep playing for a few more seconds"
+		return quality
+	}
+
+	var total int64
+	for _, drift := range s.syncSamples {
+		total += drift
+	}
+	quality.DriftMs = total / int64(quality.Samples)
+
+	switch {
+	case quality.DriftMs > syncToleranceMs:
+		quality.Suggestion = fmt.Sprintf("Lyrics appear ~%dms early, try decreasing offset", roundDrift(quality.DriftMs))
+	case quality.DriftMs < -syncToleranceMs:
+		quality.Suggestion = fmt.Sprintf("Lyrics appear ~%dms late, try increasing offset", roundDrift(-quality.DriftMs))
+	default:
+		quality.InSync = true
+		quality.Suggestion = "Lyrics appear to be in sync"
+	}
+	return quality
+}
+
+// roundDrift rounds a drift to the nearest 50ms for display
+func roundDrift(ms int64) int64 {
+	return (ms + 25) / 50 * 50
+}
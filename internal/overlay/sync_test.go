@@ -0,0 +1,143 @@
+package overlay
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetSyncQuality(t *testing.T) {
+	tests := []struct {
+		name       string
+		driftMs    int64 // Reported progress lags the extrapolation by this much
+		wantInSync bool
+		wantText   string
+	}{
+		{"in sync", 40, true, "in sync"},
+		{"lyrics early", 300, false, "~300ms early"},
+		{"lyrics late", -300, false, "~300ms late, try increasing offset"},
+	}
+
+	for _, tt := range tests {
+		s := newTestService(t)
+		start := time.Now()
+		progress := int64(10000)
+		s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: progress, IsPlaying: true, UpdatedAt: start})
+		for i := 1; i <= 5; i++ {
+			// Each poll comes 5s later, reporting progress off from the extrapolation by driftMs
+			progress += 5000 - tt.driftMs
+			s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: progress, IsPlaying: true, UpdatedAt: start.Add(time.Duration(i) * 5 * time.Second)})
+		}
+
+		quality := s.GetSyncQuality()
+		if quality.InSync != tt.wantInSync || !strings.Contains(quality.Suggestion, tt.wantText) {
+			t.Errorf("%s: GetSyncQuality = %+v; want in sync %v and %q", tt.name, quality, tt.wantInSync, tt.wantText)
+		}
+	}
+}
+
+func TestGetSyncQuality_ResetsOnSeek(t *testing.T) {
+	s := newTestService(t)
+	start := time.Now()
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 10000, IsPlaying: true, UpdatedAt: start})
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 15000, IsPlaying: true, UpdatedAt: start.Add(5 * time.Second)})
+	s.SetCurrentTrack(&TrackInfo{ID: "track", Duration: 200000, Progress: 90000, IsPlaying: true, UpdatedAt: start.Add(10 * time.Second)})
+
+	if quality := s.GetSyncQuality(); quality.Samples != 0 {
+		t.Errorf("Samples after a seek = %d; want 0", quality.Samples)
+	}
+}
+
+// gappedTestLyrics has an empty line (instrumental gap) between the first and third lines
+func gappedTestLyrics() *LyricsData {
+	return &LyricsData{
+		Source:   "Test",
+		IsSynced: true,
+		Lines: []LyricsLine{
+			{Text: "First", Timestamp: 10000},
+			{Text: "", Timestamp: 20000},
+			{Text: "Third", Timestamp: 30000},
+			{Text: "Fourth", Timestamp: 40000},
+		},
+	}
+}
+
+func TestGetDisplayInfo_Sync(t *testing.T) {
+	tests := []struct {
+		name       string
+		lyrics     *LyricsData
+		progress   int64
+		playingFor time.Duration // 0 = paused; otherwise playing since this long ago
+		offset     int64         // Overlay.SyncOffset; 0 uses the default lead
+		showEmpty  bool
+		wantLine   string
+		wantNext   string
+		wantLinePr int64 // LineProgress; -1 to skip
+		wantLineDu int64 // LineDuration; -1 to skip
+	}{
+		{"before the first line shows the intro", gappedTestLyrics(), 1000, 0, 0, false, introMarker, "First", 1350, 10000},
+		{"on the first line", gappedTestLyrics(), 15000, 0, 0, false, "First", "Third", 5350, 20000},
+		{"default lead moves to a line early", gappedTestLyrics(), 29700, 0, 0, false, "Third", "Fourth", 50, 10000},
+		{"empty gap skips ahead to the next line", gappedTestLyrics(), 22000, 0, 0, false, "Third", "Fourth", 0, 10000},
+		{"empty gap shown as a blank when enabled", gappedTestLyrics(), 22000, 0, 0, true, "", "Third", 2350, 10000},
+		{"positive offset shows lines earlier", gappedTestLyrics(), 28500, 0, 2000, false, "Third", "Fourth", 500, 10000},
+		{"negative offset shows lines later", gappedTestLyrics(), 10500, 0, -1000, false, introMarker, "First", -1, -1},
+		{"playing extrapolates from the last update", gappedTestLyrics(), 12000, 3 * time.Second, 0, false, "First", "Third", 5350, 20000},
+		{"last line past the final timestamp", gappedTestLyrics(), 45000, 0, 0, false, "Fourth", "", -1, -1},
+		{"track end holds the final line", gappedTestLyrics(), 199500, 0, 0, false, "Fourth", "", 3000, 3000},
+		{
+			"plain lyrics show the first lines",
+			&LyricsData{Source: "Test", Lines: []LyricsLine{{Text: "Plain one"}, {Text: "Plain two"}}},
+			50000, 0, 0, false, "Plain one", "Plain two", 0, 0,
+		},
+		{
+			"instrumental",
+			&LyricsData{Source: "Test", IsInstrumental: true},
+			50000, 0, 0, false, "🎸 Instrumental", "", 0, 0,
+		},
+		{
+			"no lines",
+			&LyricsData{Source: "Test"},
+			50000, 0, 0, false, "No lyrics available", "Enjoying the instrumental vibes 🎸", 0, 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			s.config.Get().Overlay.SyncOffset = tt.offset
+			s.config.Get().Overlay.ShowEmptyLines = tt.showEmpty
+			s.SetCurrentLyrics(tt.lyrics)
+			s.SetCurrentTrack(&TrackInfo{
+				ID:        "track",
+				Duration:  200000,
+				Progress:  tt.progress,
+				IsPlaying: tt.playingFor > 0,
+				UpdatedAt: time.Now().Add(-tt.playingFor),
+			})
+
+			info := s.GetDisplayInfo()
+			if info.CurrentLine != tt.wantLine || info.NextLine != tt.wantNext {
+				t.Errorf("Display = %q / %q; want %q / %q", info.CurrentLine, info.NextLine, tt.wantLine, tt.wantNext)
+			}
+			// Allow for the few ms that pass while a playing track is extrapolated
+			if tt.wantLinePr >= 0 && (info.LineProgress < tt.wantLinePr || info.LineProgress > tt.wantLinePr+200) {
+				t.Errorf("LineProgress = %d; want %d", info.LineProgress, tt.wantLinePr)
+			}
+			if tt.wantLineDu >= 0 && info.LineDuration != tt.wantLineDu {
+				t.Errorf("LineDuration = %d; want %d", info.LineDuration, tt.wantLineDu)
+			}
+			if info.IsPlaying != (tt.playingFor > 0) && tt.lyrics.IsSynced {
+				t.Errorf("IsPlaying = %v; want %v", info.IsPlaying, tt.playingFor > 0)
+			}
+		})
+	}
+}
+
+func TestGetDisplayInfo_NoTrack(t *testing.T) {
+	s := newTestService(t)
+	s.SetCurrentLyrics(gappedTestLyrics())
+	if info := s.GetDisplayInfo(); info.CurrentLine != "No track playing" {
+		t.Errorf("CurrentLine without a track = %q; want %q", info.CurrentLine, "No track playing")
+	}
+}
@@ -0,0 +1,53 @@
+package spotify
+
+import (
+	"log"
+	"time"
+
+	"lyrics-overlay/internal/overlay"
+)
+
+// zeroProgressPolls is how many polls in a row must report 0ms for the same track before its
+// progress is treated as missing rather than the very start of the song
+const zeroProgressPolls = 2
+
+// progressClock estimates progress for devices (some Spotify Connect speakers) that report 0ms
+// on every poll while playing, which would otherwise hold synced lyrics on the first line
+type progressClock struct {
+	trackID   string
+	zeroPolls int
+	progress  int64     // Estimated progress at lastPoll
+	lastPoll  time.Time // When the previous zero-progress poll arrived
+	playing   bool      // Whether the track was playing at lastPoll
+}
+
+// apply replaces a track's missing progress with a local clock started at the first
+// zero-progress poll, advancing only while playing. Returns true when track.Progress was
+// estimated.
+func (c *progressClock) apply(track *overlay.TrackInfo, now time.Time) bool {
+	if track.ID != c.trackID || track.Progress != 0 {
+		*c = progressClock{trackID: track.ID}
+		if track.Progress != 0 {
+			return false
+		}
+	}
+
+	if c.zeroPolls > 0 && c.playing {
+		c.progress += now.Sub(c.lastPoll).Milliseconds()
+	}
+	c.zeroPolls++
+	c.lastPoll = now
+	c.playing = track.IsPlaying
+	if c.zeroPolls < zeroProgressPolls {
+		return false
+	}
+	if c.zeroPolls == zeroProgressPolls {
+		log.Printf("Spotify: device keeps reporting 0ms progress for %q, estimating it locally", track.Name)
+	}
+
+	track.Progress = c.progress
+	if track.Duration > 0 {
+		track.Progress = min(track.Progress, track.Duration)
+	}
+	return true
+}
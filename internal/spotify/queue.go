@@ -0,0 +1,96 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+const (
+	// upNextTTL is how long a fetched queue is reused for the same playing track
+	upNextTTL = 30 * time.Second
+	// rateLimitCooldown is how long optional requests like the queue are skipped after a 429
+	rateLimitCooldown = 30 * time.Second
+)
+
+// UpNext is the next track in the playback queue; empty when unknown
+type UpNext struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+}
+
+// GetUpNext returns the next queued track. Results are cached per playing track for
+// upNextTTL; failures (missing scope, unsupported device, rate limiting) yield an empty result.
+func (s *Service) GetUpNext() UpNext {
+	trackID := ""
+	if s.overlay != nil {
+		if track := s.overlay.GetCurrentTrack(); track != nil {
+			trackID = track.ID
+		}
+	}
+	if trackID == "" || s.auth == nil {
+		return UpNext{}
+	}
+
+	// Don't hold queueMu over the request: the poll loop takes it in markRateLimited
+	s.queueMu.Lock()
+	now := time.Now()
+	cached, fresh := s.upNext, s.upNextTrackID == trackID && now.Sub(s.upNextAt) < upNextTTL
+	rateLimited := now.Before(s.rateLimitedUntil)
+	s.queueMu.Unlock()
+	if fresh {
+		return cached
+	}
+	if rateLimited {
+		return UpNext{}
+	}
+	client := s.auth.GetClient()
+	if client == nil {
+		return UpNext{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	queue, err := client.GetQueue(ctx)
+	next := UpNext{}
+	switch {
+	case err != nil:
+		if apiStatus(err) == http.StatusTooManyRequests {
+			s.markRateLimited()
+		}
+		log.Printf("Spotify: queue unavailable: %v", err)
+	case len(queue.Items) > 0:
+		item := queue.Items[0]
+		next = UpNext{Title: item.Name, Artist: ArtistNames(item.Artists)[0]}
+	}
+
+	// Cache failures too, so an unsupported endpoint isn't retried on every call
+	s.queueMu.Lock()
+	s.upNext, s.upNextTrackID, s.upNextAt = next, trackID, now
+	s.queueMu.Unlock()
+	return next
+}
+
+// markRateLimited pauses optional requests after the API answered 429
+func (s *Service) markRateLimited() {
+	s.queueMu.Lock()
+	s.rateLimitedUntil = time.Now().Add(rateLimitCooldown)
+	s.queueMu.Unlock()
+}
+
+// apiStatus returns the HTTP status of a Spotify API error, or 0 for other errors
+func apiStatus(err error) int {
+	var apiErr spotify.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status
+	}
+	var apiErrPtr *spotify.Error
+	if errors.As(err, &apiErrPtr) {
+		return apiErrPtr.Status
+	}
+	return 0
+}
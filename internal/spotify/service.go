@@ -2,43 +2,92 @@ package spotify
 
 import (
 	"context"
+	"errors"
+	"log"
+	"net"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
+	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"github.com/zmb3/spotify/v2"
 
 	"lyrics-overlay/internal/auth"
+	"lyrics-overlay/internal/history"
 	"lyrics-overlay/internal/lyrics"
 	"lyrics-overlay/internal/overlay"
 )
 
+// maxLatencyCorrection caps how far progress is advanced to make up for request latency
+const maxLatencyCorrection = 500 * time.Millisecond
+
 // Service handles Spotify API interactions and polling
 type Service struct {
+	ctx               context.Context // Wails runtime context; nil outside the app (e.g. tests)
 	auth              *auth.Service
 	overlay           *overlay.Service
 	lyrics            *lyrics.Service
+	history           *history.Service
 	stopChan          chan struct{}
 	isPolling         bool
 	baseInterval      time.Duration
 	currentInterval   time.Duration
 	backoffFactor     float64
 	maxInterval       time.Duration
+	catchUpInterval   time.Duration // Fast interval used right after a track change
+	catchUpPolls      int           // Number of fast polls after a track change
+	catchUpRemaining  int
 	lastTrackID       string
+	lastDeviceID      string
 	consecutiveErrors int
+	networkErrors     bool // Backoff was caused by network failures; snap back once they clear
+	rescopeRequested  bool // "auth:rescope" was emitted for the current run of 403s
+	progressClock     progressClock
+
+	// Guards lastDeviceName, read by GetActiveDevice while the poll loop writes it
+	deviceMu       sync.Mutex
+	lastDeviceName string
+
+	fetchMu     sync.Mutex
+	fetchCancel context.CancelFunc // Cancels the in-flight lyrics lookup, if any
+
+	// Up-next cache for GetUpNext, keyed by the playing track
+	queueMu          sync.Mutex
+	upNext           UpNext
+	upNextTrackID    string
+	upNextAt         time.Time
+	rateLimitedUntil time.Time // Optional requests are skipped until then after a 429
 }
 
 // New creates a new Spotify service
-func New(authSvc *auth.Service, overlaySvc *overlay.Service, lyricsSvc *lyrics.Service) *Service {
+func New(authSvc *auth.Service, overlaySvc *overlay.Service, lyricsSvc *lyrics.Service, historySvc *history.Service) *Service {
 	return &Service{
 		auth:            authSvc,
 		overlay:         overlaySvc,
 		lyrics:          lyricsSvc,
+		history:         historySvc,
 		stopChan:        make(chan struct{}),
 		baseInterval:    5 * time.Second,  // Faster polling when playing
 		currentInterval: 5 * time.Second,  // Current polling interval
 		backoffFactor:   1.5,              // Exponential backoff factor
 		maxInterval:     30 * time.Second, // Maximum polling interval
+		catchUpInterval: 1 * time.Second,  // Burst polling after a track change
+		catchUpPolls:    3,
+	}
+}
+
+// SetContext provides the Wails runtime context used to emit events; call before Start
+func (s *Service) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// emit sends an event to the frontend; a no-op without a runtime context
+func (s *Service) emit(event string, data ...interface{}) {
+	if s.ctx == nil {
+		return
 	}
+	runtime.EventsEmit(s.ctx, event, data...)
 }
 
 // Start begins the Spotify polling service
@@ -47,7 +96,10 @@ func (s *Service) Start() {
 		return
 	}
 	s.isPolling = true
-	go s.pollLoop()
+	// A fresh channel per run; Stop closes it, and only this run's loop watches it
+	stop := make(chan struct{})
+	s.stopChan = stop
+	go s.pollLoop(stop)
 }
 
 // Stop stops the Spotify polling service
@@ -57,22 +109,31 @@ func (s *Service) Stop() {
 	}
 	s.isPolling = false
 	close(s.stopChan)
+	s.cancelFetch()
 }
 
-// pollLoop is the main polling loop
-func (s *Service) pollLoop() {
+// pollLoop is the main polling loop; it runs until stop is closed
+func (s *Service) pollLoop(stop <-chan struct{}) {
 	ticker := time.NewTicker(s.currentInterval)
 	defer ticker.Stop()
 
+	lastTick := time.Now()
 	for {
 		select {
-		case <-s.stopChan:
+		case <-stop:
 			return
 		case <-ticker.C:
+			now := time.Now()
+			if sleptBetween(lastTick, now, s.currentInterval) {
+				s.handleResume(now.Round(0).Sub(lastTick.Round(0)))
+			}
+			lastTick = now
+
 			s.pollCurrentlyPlaying()
 
 			// Update ticker with current interval
 			ticker.Reset(s.currentInterval)
+			s.overlay.SetPollInterval(s.currentInterval)
 		}
 	}
 }
@@ -88,34 +149,60 @@ func (s *Service) pollCurrentlyPlaying() {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	playerState, err := client.PlayerCurrentlyPlaying(ctx)
+	// PlayerState includes the active device alongside the currently playing item
+	requestStart := time.Now()
+	playerState, err := client.PlayerState(ctx)
+	roundTrip := time.Since(requestStart)
 	if err != nil {
 		s.handleError(err)
 		return
 	}
+	s.markPollSuccess()
 
 	if playerState == nil || playerState.Item == nil {
 		s.handleNoPlayback()
 		return
 	}
 
-	// Extract track information
+	// Extract track information; a locally estimated progress needs no latency correction
 	track := s.extractTrackInfo(playerState)
+	if !s.progressClock.apply(track, time.Now()) {
+		CorrectForLatency(track, roundTrip)
+	}
 
 	// Check if track changed
-	if track.ID != s.lastTrackID {
+	trackChanged := track.ID != s.lastTrackID
+	if trackChanged {
 		s.lastTrackID = track.ID
 		s.resetInterval()
+		s.catchUpRemaining = s.catchUpPolls
+		s.emit("track:changed", track)
+
+		if s.history != nil {
+			s.history.Record(track)
+		}
+	}
 
-		// Fetch lyrics on track change
-		if s.lyrics != nil {
-			go s.fetchAndSetLyrics(track)
+	// A device switch mid-song jumps progress; treat it like a seek and resync quickly
+	if track.DeviceID != s.lastDeviceID {
+		if s.lastDeviceID != "" {
+			log.Printf("Spotify: playback moved to device %q", track.DeviceName)
+			s.resetInterval()
 		}
+		s.lastDeviceID = track.DeviceID
+		s.deviceMu.Lock()
+		s.lastDeviceName = track.DeviceName
+		s.deviceMu.Unlock()
 	}
 
 	// Update overlay with current track
 	s.overlay.SetCurrentTrack(track)
 
+	// Fetch lyrics on track change, once the overlay knows the new track
+	if trackChanged && s.lyrics != nil {
+		s.FetchLyrics(track)
+	}
+
 	// Adjust polling based on playback state
 	if track.IsPlaying {
 		s.adjustInterval(true, false)
@@ -127,49 +214,146 @@ func (s *Service) pollCurrentlyPlaying() {
 	s.consecutiveErrors = 0
 }
 
-// fetchAndSetLyrics queries the lyrics service and updates the overlay
-func (s *Service) fetchAndSetLyrics(track *overlay.TrackInfo) {
-	artist := ""
-	if len(track.Artists) > 0 {
-		artist = track.Artists[0]
+// FetchLyrics looks up lyrics for track in the background and updates the overlay, cancelling
+// any lookup still running for a previous track so only one is ever in flight
+func (s *Service) FetchLyrics(track *overlay.TrackInfo) {
+	if s.lyrics == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.fetchMu.Lock()
+	if s.fetchCancel != nil {
+		s.fetchCancel()
+	}
+	s.fetchCancel = cancel
+	s.fetchMu.Unlock()
+
+	go func() {
+		defer cancel()
+		s.fetchLyrics(ctx, track)
+	}()
+}
+
+// cancelFetch stops the in-flight lyrics lookup, if any
+func (s *Service) cancelFetch() {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	if s.fetchCancel != nil {
+		s.fetchCancel()
+		s.fetchCancel = nil
 	}
-	lyrics, err := s.lyrics.GetLyrics(track.ID, artist, track.Name)
-	if err != nil || lyrics == nil {
-		// Clear lyrics if not found to avoid stale display
-		s.overlay.SetCurrentLyrics(nil)
+}
+
+// fetchLyrics runs a lookup and applies the result, unless it was cancelled or the track
+// changed while it was in flight
+func (s *Service) fetchLyrics(ctx context.Context, track *overlay.TrackInfo) {
+	data, err := s.lyrics.GetLyricsForTrack(ctx, track)
+	if ctx.Err() != nil {
+		log.Printf("Spotify: lyrics lookup for %s cancelled", track.Name)
 		return
 	}
-	s.overlay.SetCurrentLyrics(lyrics)
+	switch {
+	case err == nil && data != nil, errors.Is(err, lyrics.ErrInstrumental):
+		// Instrumentals come back with data so the overlay can show the instrumental state
+	case errors.Is(err, lyrics.ErrProviderUnavailable):
+		log.Printf("Spotify: lyrics providers unreachable for %s: %v", track.Name, err)
+		data = &overlay.LyricsData{
+			TrackID:   track.ID,
+			Source:    "Info",
+			FetchedAt: time.Now(),
+			Lines:     []overlay.LyricsLine{{Text: "⚠️ Lyrics unavailable — check your connection"}},
+		}
+	default:
+		// Clear lyrics if not found to avoid stale display
+		data = nil
+	}
+	if s.history != nil && data != nil {
+		s.history.SetLyricsFound(track.ID, data.HasLyrics())
+	}
+	if !s.overlay.SetLyricsForTrack(track.ID, data) {
+		log.Printf("Spotify: discarding lyrics for %s, track changed during lookup", track.Name)
+	}
+}
+
+// UnknownArtist stands in for items Spotify returns without artists (some local files and podcasts)
+const UnknownArtist = "Unknown Artist"
+
+// ArtistNames returns the artists' names, or just UnknownArtist when there are none, so callers
+// can always use the first entry
+func ArtistNames(artists []spotify.SimpleArtist) []string {
+	if len(artists) == 0 {
+		return []string{UnknownArtist}
+	}
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
 }
 
 // extractTrackInfo extracts track information from Spotify API response
-func (s *Service) extractTrackInfo(playerState *spotify.CurrentlyPlaying) *overlay.TrackInfo {
+func (s *Service) extractTrackInfo(playerState *spotify.PlayerState) *overlay.TrackInfo {
 	track := playerState.Item
 
-	artists := make([]string, len(track.Artists))
-	for i, artist := range track.Artists {
-		artists[i] = artist.Name
+	return &overlay.TrackInfo{
+		ID:         track.ID.String(),
+		Name:       track.Name,
+		Artists:    ArtistNames(track.Artists),
+		Album:      track.Album.Name,
+		Duration:   int64(track.Duration),
+		Progress:   int64(playerState.Progress),
+		IsPlaying:  playerState.Playing,
+		UpdatedAt:  time.Now(),
+		DeviceID:   playerState.Device.ID.String(),
+		DeviceName: playerState.Device.Name,
+		ISRC:       track.ExternalIDs["isrc"],
+		Explicit:   track.Explicit,
 	}
+}
 
-	return &overlay.TrackInfo{
-		ID:        track.ID.String(),
-		Name:      track.Name,
-		Artists:   artists,
-		Album:     track.Album.Name,
-		Duration:  int64(track.Duration),
-		Progress:  int64(playerState.Progress),
-		IsPlaying: playerState.Playing,
-		UpdatedAt: time.Now(),
+// CorrectForLatency advances a playing track's progress by half the request round trip, since
+// Spotify measured it roughly that long before the response arrived. The correction is capped
+// so a latency spike doesn't push lyrics ahead of the music.
+func CorrectForLatency(track *overlay.TrackInfo, roundTrip time.Duration) {
+	if track == nil || !track.IsPlaying || roundTrip <= 0 {
+		return
+	}
+	correction := roundTrip / 2
+	if correction > maxLatencyCorrection {
+		correction = maxLatencyCorrection
+	}
+	track.Progress += correction.Milliseconds()
+	if track.Duration > 0 && track.Progress > track.Duration {
+		track.Progress = track.Duration
 	}
 }
 
 // handleError handles API errors with appropriate backoff
 func (s *Service) handleError(err error) {
 	s.consecutiveErrors++
+	s.catchUpRemaining = 0 // Never burst while backing off
+	if isNetworkError(err) {
+		s.networkErrors = true
+	}
 
 	// Check for rate limiting (429)
-	if httpErr, ok := err.(*spotify.Error); ok && httpErr.Status == http.StatusTooManyRequests {
-		s.handleRateLimit(httpErr)
+	status := apiStatus(err)
+	if status == http.StatusTooManyRequests {
+		s.handleRateLimit()
+		return
+	}
+
+	// 403 means the token lacks a required scope; backing off won't fix it, so ask the user to
+	// re-authenticate (once per episode) and stop showing the stale track
+	if status == http.StatusForbidden {
+		s.auth.MarkScopeError()
+		if !s.rescopeRequested {
+			s.rescopeRequested = true
+			log.Printf("Spotify: request forbidden (%s), re-authentication needed", err)
+			s.emit("auth:rescope", s.auth.MissingScopes())
+			s.overlay.SetCurrentTrack(nil)
+		}
+		s.adjustInterval(false, true)
 		return
 	}
 
@@ -184,14 +368,34 @@ func (s *Service) handleError(err error) {
 	}
 }
 
+// markPollSuccess resets the backoff right away when the network comes back after an outage
+func (s *Service) markPollSuccess() {
+	s.rescopeRequested = false
+	if !s.networkErrors {
+		return
+	}
+	log.Printf("Spotify: connectivity restored after %d errors, resuming normal polling", s.consecutiveErrors)
+	s.networkErrors = false
+	s.resetInterval()
+}
+
+// isNetworkError reports whether err is a transport failure rather than an API error response
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
 // handleRateLimit handles 429 rate limit responses
-func (s *Service) handleRateLimit(err *spotify.Error) {
+func (s *Service) handleRateLimit() {
 	s.currentInterval = s.maxInterval
+	s.catchUpRemaining = 0
+	s.markRateLimited()
 }
 
 // handleNoPlayback handles when there's no currently playing content
 func (s *Service) handleNoPlayback() {
-	s.overlay.SetCurrentTrack(nil)
+	s.overlay.EndTrack()
 	s.adjustInterval(false, true)
 }
 
@@ -203,6 +407,10 @@ func (s *Service) adjustInterval(isPlaying, hasError bool) {
 		if s.currentInterval > s.maxInterval {
 			s.currentInterval = s.maxInterval
 		}
+	} else if isPlaying && s.catchUpRemaining > 0 {
+		// Short burst of fast polls right after a track change to tighten initial sync
+		s.currentInterval = s.catchUpInterval
+		s.catchUpRemaining--
 	} else if isPlaying {
 		// Faster polling when music is playing
 		s.currentInterval = s.baseInterval
@@ -212,6 +420,29 @@ func (s *Service) adjustInterval(isPlaying, hasError bool) {
 	}
 }
 
+// resumeGapThreshold is how much later than scheduled a poll must run to count as a wake from sleep
+const resumeGapThreshold = 30 * time.Second
+
+// sleptBetween reports whether the machine likely slept between two poll ticks. Wall-clock time
+// is compared because the monotonic clock can stop while suspended.
+func sleptBetween(last, now time.Time, interval time.Duration) bool {
+	return now.Round(0).Sub(last.Round(0)) > interval+resumeGapThreshold
+}
+
+// handleResume prepares the first poll after sleep: the token is refreshed up front and any
+// error backoff from before (or while) sleeping is dropped, so the overlay recovers right away
+func (s *Service) handleResume(gap time.Duration) {
+	log.Printf("Spotify: %v since the last poll, resuming after sleep", gap.Round(time.Second))
+	s.resetInterval()
+	s.networkErrors = false
+	s.rescopeRequested = false
+	if s.auth != nil {
+		if err := s.auth.RefreshAfterResume(); err != nil {
+			log.Printf("Spotify: token refresh after resume failed: %v", err)
+		}
+	}
+}
+
 // resetInterval resets the polling interval to base value
 func (s *Service) resetInterval() {
 	s.currentInterval = s.baseInterval
@@ -223,6 +454,13 @@ func (s *Service) GetCurrentTrack() *overlay.TrackInfo {
 	return s.overlay.GetCurrentTrack()
 }
 
+// GetActiveDevice returns the name of the device last seen playing
+func (s *Service) GetActiveDevice() string {
+	s.deviceMu.Lock()
+	defer s.deviceMu.Unlock()
+	return s.lastDeviceName
+}
+
 // IsPolling returns whether the service is currently polling
 func (s *Service) IsPolling() bool {
 	return s.isPolling
@@ -0,0 +1,337 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+
+	"lyrics-overlay/internal/auth"
+	"lyrics-overlay/internal/cache"
+	"lyrics-overlay/internal/config"
+	"lyrics-overlay/internal/lyrics"
+	"lyrics-overlay/internal/overlay"
+)
+
+// newTestService creates a Spotify service with a real overlay and no auth or lyrics
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	configSvc, err := config.NewWithPath(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("config.NewWithPath failed: %v", err)
+	}
+	overlaySvc, err := overlay.New(configSvc)
+	if err != nil {
+		t.Fatalf("overlay.New failed: %v", err)
+	}
+	return New(nil, overlaySvc, nil, nil)
+}
+
+func TestAdjustInterval_CatchUpAfterTrackChange(t *testing.T) {
+	s := New(nil, nil, nil, nil)
+	s.catchUpRemaining = s.catchUpPolls
+
+	for i := 0; i < 3; i++ {
+		s.adjustInterval(true, false)
+		if s.currentInterval != s.catchUpInterval {
+			t.Fatalf("Poll %d: interval = %v; want catch-up %v", i, s.currentInterval, s.catchUpInterval)
+		}
+	}
+
+	s.adjustInterval(true, false)
+	if s.currentInterval != s.baseInterval {
+		t.Errorf("After burst: interval = %v; want base %v", s.currentInterval, s.baseInterval)
+	}
+}
+
+func TestAdjustInterval_NoCatchUpWhenPaused(t *testing.T) {
+	s := New(nil, nil, nil, nil)
+	s.catchUpRemaining = s.catchUpPolls
+
+	s.adjustInterval(false, false)
+	if s.currentInterval != s.baseInterval*3 {
+		t.Errorf("Paused interval = %v; want %v", s.currentInterval, s.baseInterval*3)
+	}
+}
+
+func TestAdjustInterval_Backoff(t *testing.T) {
+	s := New(nil, nil, nil, nil)
+
+	for i := 0; i < 10; i++ {
+		s.adjustInterval(false, true)
+	}
+	if s.currentInterval != s.maxInterval {
+		t.Errorf("Interval after repeated errors = %v; want max %v", s.currentInterval, s.maxInterval)
+	}
+	if s.maxInterval != 30*time.Second {
+		t.Errorf("Unexpected max interval %v", s.maxInterval)
+	}
+}
+
+func TestMarkPollSuccess_ResetsAfterNetworkErrors(t *testing.T) {
+	s := newTestService(t)
+
+	// Error storm while offline pushes polling to the max interval
+	offline := &url.Error{Op: "Get", URL: "https://api.spotify.com/v1/me/player", Err: errors.New("dial tcp: no such host")}
+	for i := 0; i < 10; i++ {
+		s.handleError(offline)
+	}
+	if s.currentInterval != s.maxInterval {
+		t.Fatalf("Interval after error storm = %v; want max %v", s.currentInterval, s.maxInterval)
+	}
+
+	// First successful poll snaps straight back
+	s.markPollSuccess()
+	if s.currentInterval != s.baseInterval {
+		t.Errorf("Interval after recovery = %v; want base %v", s.currentInterval, s.baseInterval)
+	}
+	if s.consecutiveErrors != 0 {
+		t.Errorf("consecutiveErrors = %d; want 0", s.consecutiveErrors)
+	}
+}
+
+func TestMarkPollSuccess_KeepsBackoffAfterAPIErrors(t *testing.T) {
+	s := newTestService(t)
+
+	for i := 0; i < 10; i++ {
+		s.handleError(&spotify.Error{Status: http.StatusInternalServerError, Message: "server error"})
+	}
+	s.markPollSuccess()
+	if s.currentInterval != s.maxInterval {
+		t.Errorf("Interval = %v; want backoff kept at %v for API errors", s.currentInterval, s.maxInterval)
+	}
+}
+
+func TestCorrectForLatency(t *testing.T) {
+	tests := []struct {
+		name      string
+		track     overlay.TrackInfo
+		roundTrip time.Duration
+		want      int64
+	}{
+		{"half the round trip", overlay.TrackInfo{IsPlaying: true, Progress: 10000, Duration: 200000}, 300 * time.Millisecond, 10150},
+		{"capped on spikes", overlay.TrackInfo{IsPlaying: true, Progress: 10000, Duration: 200000}, 5 * time.Second, 10000 + maxLatencyCorrection.Milliseconds()},
+		{"paused is untouched", overlay.TrackInfo{IsPlaying: false, Progress: 10000, Duration: 200000}, 300 * time.Millisecond, 10000},
+		{"clamped to duration", overlay.TrackInfo{IsPlaying: true, Progress: 199950, Duration: 200000}, 300 * time.Millisecond, 200000},
+	}
+
+	for _, tt := range tests {
+		track := tt.track
+		CorrectForLatency(&track, tt.roundTrip)
+		if track.Progress != tt.want {
+			t.Errorf("%s: Progress = %d; want %d", tt.name, track.Progress, tt.want)
+		}
+	}
+}
+
+func TestHandleError_ForbiddenRequestsRescope(t *testing.T) {
+	s := newTestService(t)
+	configSvc, err := config.NewWithPath(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("config.NewWithPath failed: %v", err)
+	}
+	configSvc.Get().SpotifyClientID = "client-id"
+	configSvc.Get().SpotifyClientSecret = "client-secret"
+	authSvc, err := auth.New(configSvc)
+	if err != nil {
+		t.Fatalf("auth.New failed: %v", err)
+	}
+	t.Cleanup(authSvc.Close)
+	s.auth = authSvc
+
+	s.overlay.SetCurrentTrack(&overlay.TrackInfo{ID: "track", UpdatedAt: time.Now()})
+	forbidden := spotify.Error{Status: http.StatusForbidden, Message: "Insufficient client scope"}
+	s.handleError(forbidden)
+
+	if !authSvc.NeedsReauth() {
+		t.Error("Expected a 403 to flag re-authentication")
+	}
+	if !s.rescopeRequested {
+		t.Error("Expected a rescope request after a 403")
+	}
+	if s.overlay.GetCurrentTrack() != nil {
+		t.Error("Expected the stale track to be cleared after a 403")
+	}
+
+	s.markPollSuccess()
+	if s.rescopeRequested {
+		t.Error("Expected a successful poll to reset the rescope request")
+	}
+}
+
+func TestStartAfterStop(t *testing.T) {
+	s := newTestService(t)
+	s.Start()
+	s.Stop()
+	s.Start()
+	defer s.Stop()
+
+	// A restarted loop must not see the previous run's closed stop channel
+	select {
+	case <-s.stopChan:
+		t.Error("Expected a fresh stop channel after restarting")
+	default:
+	}
+}
+
+// blockingProvider waits for its context on the first lookup and answers the rest immediately
+type blockingProvider struct {
+	calls     chan string
+	cancelled chan struct{}
+}
+
+func (p *blockingProvider) GetName() string { return "Blocking" }
+
+func (p *blockingProvider) SearchLyrics(ctx context.Context, artist, title string) (*overlay.LyricsData, error) {
+	p.calls <- title
+	if title == "Slow" {
+		<-ctx.Done()
+		close(p.cancelled)
+		return nil, ctx.Err()
+	}
+	return &overlay.LyricsData{Source: "Blocking", Lines: []overlay.LyricsLine{{Text: title}}}, nil
+}
+
+func TestFetchLyrics_CancelsPreviousLookup(t *testing.T) {
+	s := newTestService(t)
+	provider := &blockingProvider{calls: make(chan string, 2), cancelled: make(chan struct{})}
+	s.lyrics = lyrics.NewWithProviders(cache.New(10), provider)
+
+	slow := &overlay.TrackInfo{ID: "slow", Name: "Slow", Artists: []string{"Artist"}, UpdatedAt: time.Now()}
+	s.overlay.SetCurrentTrack(slow)
+	s.FetchLyrics(slow)
+	<-provider.calls
+
+	fast := &overlay.TrackInfo{ID: "fast", Name: "Fast", Artists: []string{"Artist"}, UpdatedAt: time.Now()}
+	s.overlay.SetCurrentTrack(fast)
+	s.FetchLyrics(fast)
+
+	select {
+	case <-provider.cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the stale lookup to be cancelled")
+	}
+	<-provider.calls
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.overlay.GetCurrentLyrics() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := s.overlay.GetCurrentLyrics(); got == nil || got.Lines[0].Text != "Fast" {
+		t.Errorf("Lyrics = %+v; want the new track's", got)
+	}
+}
+
+func TestExtractTrackInfo_NoArtists(t *testing.T) {
+	s := New(nil, nil, nil, nil)
+	state := &spotify.PlayerState{CurrentlyPlaying: spotify.CurrentlyPlaying{Item: &spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{Name: "Local file"}}}}
+
+	track := s.extractTrackInfo(state)
+	if len(track.Artists) != 1 || track.Artists[0] != UnknownArtist {
+		t.Errorf("Artists = %v; want [%s]", track.Artists, UnknownArtist)
+	}
+}
+
+func TestGetUpNext_DegradesGracefully(t *testing.T) {
+	s := newTestService(t)
+	if next := s.GetUpNext(); next != (UpNext{}) {
+		t.Errorf("GetUpNext without a track or auth = %+v; want empty", next)
+	}
+
+	// 429s are recognized whether the client returns the error by value or pointer
+	for _, err := range []error{spotify.Error{Status: http.StatusTooManyRequests}, &spotify.Error{Status: http.StatusTooManyRequests}} {
+		if got := apiStatus(err); got != http.StatusTooManyRequests {
+			t.Errorf("apiStatus(%T) = %d; want 429", err, got)
+		}
+	}
+	if got := apiStatus(errors.New("boom")); got != 0 {
+		t.Errorf("apiStatus(non-API error) = %d; want 0", got)
+	}
+
+	s.handleError(spotify.Error{Status: http.StatusTooManyRequests})
+	if !time.Now().Before(s.rateLimitedUntil) {
+		t.Error("Expected a 429 to pause queue requests")
+	}
+}
+
+func TestHandleResume_ResetsBackoff(t *testing.T) {
+	now := time.Now()
+	if sleptBetween(now.Add(-6*time.Second), now, 5*time.Second) {
+		t.Error("A slightly late tick should not count as sleep")
+	}
+	if !sleptBetween(now.Add(-10*time.Minute), now, 5*time.Second) {
+		t.Error("A 10 minute gap should count as sleep")
+	}
+
+	s := newTestService(t)
+	for i := 0; i < 10; i++ {
+		s.handleError(&spotify.Error{Status: http.StatusInternalServerError, Message: "server error"})
+	}
+	s.handleResume(10 * time.Minute)
+	if s.currentInterval != s.baseInterval || s.consecutiveErrors != 0 {
+		t.Errorf("After resume: interval %v, errors %d; want base %v and 0", s.currentInterval, s.consecutiveErrors, s.baseInterval)
+	}
+}
+
+func TestProgressClock_ZeroProgressPolls(t *testing.T) {
+	var clock progressClock
+	start := time.Now()
+	polls := []struct {
+		after     time.Duration
+		progress  int64
+		playing   bool
+		want      int64
+		estimated bool
+	}{
+		{0, 0, true, 0, false},             // Could be the very start of the song
+		{time.Second, 0, true, 1000, true}, // Still 0 while playing: estimate
+		{6 * time.Second, 0, true, 6000, true},
+		{11 * time.Second, 0, false, 11000, true}, // Paused; the clock stops
+		{20 * time.Second, 0, false, 11000, true},
+		{25 * time.Second, 0, true, 11000, true}, // Resumed
+		{30 * time.Second, 0, true, 16000, true},
+		{35 * time.Second, 42000, true, 42000, false}, // Real progress again
+	}
+
+	for i, poll := range polls {
+		track := &overlay.TrackInfo{ID: "track", Name: "Song", Duration: 200000, Progress: poll.progress, IsPlaying: poll.playing}
+		estimated := clock.apply(track, start.Add(poll.after))
+		if estimated != poll.estimated || track.Progress != poll.want {
+			t.Errorf("Poll %d: progress %d (estimated %v); want %d (%v)", i, track.Progress, estimated, poll.want, poll.estimated)
+		}
+	}
+
+	// A song that really starts at 0 moves on by the next poll and is never estimated
+	clock = progressClock{}
+	for i, progress := range []int64{0, 1200} {
+		track := &overlay.TrackInfo{ID: "other", Progress: progress, IsPlaying: true}
+		if clock.apply(track, start.Add(time.Duration(i)*time.Second)) {
+			t.Errorf("Poll %d at %dms was estimated; want the reported progress", i, progress)
+		}
+	}
+}
+
+func TestProgressClock_MovesPastFirstLine(t *testing.T) {
+	s := newTestService(t)
+	s.overlay.SetCurrentLyrics(&overlay.LyricsData{Source: "Test", IsSynced: true, Lines: []overlay.LyricsLine{
+		{Text: "First line", Timestamp: 0},
+		{Text: "Second line", Timestamp: 8000},
+	}})
+
+	start := time.Now().Add(-10 * time.Second)
+	for _, at := range []time.Time{start, time.Now()} {
+		track := &overlay.TrackInfo{ID: "track", Name: "Song", Duration: 200000, IsPlaying: true, UpdatedAt: at}
+		s.progressClock.apply(track, at)
+		s.overlay.SetCurrentTrack(track)
+	}
+
+	if line := s.overlay.GetDisplayInfo().CurrentLine; line != "Second line" {
+		t.Errorf("CurrentLine after 10s of zero-progress polls = %q; want %q", line, "Second line")
+	}
+}
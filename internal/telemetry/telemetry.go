@@ -0,0 +1,168 @@
+package telemetry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"lyrics-overlay/internal/config"
+)
+
+// maxRecords bounds the local match log
+const maxRecords = 500
+
+// saveDelay is how long changes wait before the log is written, coalescing lookups in a burst
+const saveDelay = 2 * time.Second
+
+// Service keeps a local, never-uploaded log of lyrics matches to help diagnose wrong lyrics
+type Service struct {
+	mu        sync.Mutex
+	path      string
+	records   []Record    // Oldest first
+	saveTimer *time.Timer // Pending save, nil when none
+}
+
+// Record describes one lyrics lookup. Artist and title are stored normalized.
+type Record struct {
+	TrackKey        string    `json:"track_key,omitempty"` // Hash of the track ID, only used to link corrections
+	Time            time.Time `json:"time"`
+	Artist          string    `json:"artist"`
+	Title           string    `json:"title"`
+	DurationMs      int64     `json:"duration_ms"`
+	Provider        string    `json:"provider"`
+	MatchConfidence float64   `json:"match_confidence"`
+	Synced          bool      `json:"synced"`
+	Found           bool      `json:"found"`
+	Corrected       bool      `json:"corrected"` // User later cleared or replaced the lyrics
+}
+
+// Report is the anonymized export of the match log
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Records     []Record  `json:"records"`
+}
+
+// New creates a telemetry service persisting to path, loading any existing log
+func New(path string) (*Service, error) {
+	service := &Service{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return service, nil
+		}
+		return nil, fmt.Errorf("failed to read match log: %w", err)
+	}
+	if err := json.Unmarshal(data, &service.records); err != nil {
+		// A damaged log isn't worth failing over; start a fresh one
+		service.records = nil
+	}
+	return service, nil
+}
+
+// TrackKey returns the opaque key used to link a record to later corrections
+func TrackKey(trackID string) string {
+	if trackID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(trackID))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Record appends a lookup to the log; it is written to disk shortly after
+func (s *Service) Record(record Record) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	if len(s.records) > maxRecords {
+		s.records = s.records[len(s.records)-maxRecords:]
+	}
+	s.saveLaterLocked()
+	return nil
+}
+
+// MarkCorrected flags the most recent lookup for the track as corrected by the user
+func (s *Service) MarkCorrected(trackID string) error {
+	key := TrackKey(trackID)
+	if key == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if s.records[i].TrackKey == key {
+			s.records[i].Corrected = true
+			s.saveLaterLocked()
+			return nil
+		}
+	}
+	return nil
+}
+
+// Records returns a copy of the log, oldest first
+func (s *Service) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Record(nil), s.records...)
+}
+
+// ExportReport writes an anonymized report to path: track keys are dropped and
+// timestamps are reduced to the day
+func (s *Service) ExportReport(path string) error {
+	report := Report{GeneratedAt: time.Now().UTC().Truncate(24 * time.Hour)}
+	for _, record := range s.Records() {
+		record.TrackKey = ""
+		record.Time = record.Time.UTC().Truncate(24 * time.Hour)
+		report.Records = append(report.Records, record)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Flush writes a pending save immediately, if there is one
+func (s *Service) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveTimer == nil {
+		return nil
+	}
+	s.saveTimer.Stop()
+	s.saveTimer = nil
+	return s.saveLocked()
+}
+
+// saveLaterLocked schedules a save after saveDelay unless one is pending (must hold lock)
+func (s *Service) saveLaterLocked() {
+	if s.saveTimer != nil {
+		return // Already scheduled; it will pick up this change too
+	}
+	s.saveTimer = time.AfterFunc(saveDelay, func() {
+		if err := s.Flush(); err != nil {
+			log.Printf("Telemetry: failed to save match log: %v", err)
+		}
+	})
+}
+
+// saveLocked writes the log to disk (must hold lock)
+func (s *Service) saveLocked() error {
+	data, err := json.Marshal(s.records)
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(s.path, data)
+}
@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "match_log.json")
+
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := s.Record(Record{TrackKey: TrackKey("track1"), Artist: "artist", Title: "song", Provider: "LRCLIB", Found: true}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	// Saves are debounced; Flush writes the pending one
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the log to be written after a delay, got stat error %v", err)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	reloaded, err := New(path)
+	if err != nil {
+		t.Fatalf("New (reload) failed: %v", err)
+	}
+	if records := reloaded.Records(); len(records) != 1 || records[0].Title != "song" {
+		t.Errorf("Reloaded records = %+v; want the recorded lookup", records)
+	}
+}
+
+func TestMarkCorrected(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "match_log.json"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	s.Record(Record{TrackKey: TrackKey("track1"), Title: "first"})
+	s.Record(Record{TrackKey: TrackKey("track2"), Title: "other"})
+	s.Record(Record{TrackKey: TrackKey("track1"), Title: "replay"})
+
+	if err := s.MarkCorrected("track1"); err != nil {
+		t.Fatalf("MarkCorrected failed: %v", err)
+	}
+
+	records := s.Records()
+	if records[0].Corrected || records[1].Corrected || !records[2].Corrected {
+		t.Errorf("Only the latest lookup for track1 should be corrected: %+v", records)
+	}
+}
+
+func TestRecord_Bounded(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "match_log.json"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	for i := 0; i < maxRecords+10; i++ {
+		s.Record(Record{Title: "song"})
+	}
+	if got := len(s.Records()); got != maxRecords {
+		t.Errorf("Records = %d; want %d", got, maxRecords)
+	}
+}
+
+func TestExportReport_Anonymized(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(filepath.Join(dir, "match_log.json"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	s.Record(Record{TrackKey: TrackKey("track1"), Time: time.Date(2024, 5, 1, 13, 45, 0, 0, time.UTC), Title: "song"})
+
+	reportPath := filepath.Join(dir, "report.json")
+	if err := s.ExportReport(reportPath); err != nil {
+		t.Fatalf("ExportReport failed: %v", err)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(report.Records) != 1 {
+		t.Fatalf("Report records = %d; want 1", len(report.Records))
+	}
+	record := report.Records[0]
+	if record.TrackKey != "" {
+		t.Errorf("Export should drop track keys, got %q", record.TrackKey)
+	}
+	if !record.Time.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Export time = %v; want day precision", record.Time)
+	}
+}
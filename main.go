@@ -3,9 +3,15 @@ package main
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"path/filepath"
@@ -20,9 +26,11 @@ import (
 	"lyrics-overlay/internal/auth"
 	"lyrics-overlay/internal/cache"
 	"lyrics-overlay/internal/config"
+	"lyrics-overlay/internal/history"
 	"lyrics-overlay/internal/lyrics"
 	"lyrics-overlay/internal/overlay"
 	"lyrics-overlay/internal/spotify"
+	"lyrics-overlay/internal/telemetry"
 )
 
 //go:embed all:frontend/dist
@@ -30,20 +38,68 @@ var assets embed.FS
 
 // App struct
 type App struct {
-	ctx     context.Context
+	ctx   context.Context
+	cache *cache.Service
+	// The active profile's services; read them with services()
+	active atomic.Pointer[profileServices]
+	// Serializes profile service restarts
+	restartMu sync.Mutex
+	// Pinned lyrics file, next to the default profile's config like the cache they belong to
+	pinnedPath string
+	// Closing stopCachePruner ends the periodic cache prune
+	stopCachePruner chan struct{}
+
+	// Demo playback: closing demoStop ends it; polling resumes if it was running before
+	demoMu            sync.Mutex
+	demoStop          chan struct{}
+	demoResumePolling bool
+
+	// External control: SetExternalProgress drives the overlay while Spotify polling is suspended
+	externalMu            sync.Mutex
+	externalControl       bool
+	externalResumePolling bool
+
+	// Opacity fade: closing opacityStop cancels it
+	opacityMu   sync.Mutex
+	opacityStop chan struct{}
+
+	// Windows-specific: manage click-through state for overlay during games
+	overlayHWND      uintptr
+	clickThrough     bool
+	stopClickMonitor chan struct{}
+	movedForGame     bool // Overlay moved to Overlay.InGamePosition; restore X/Y when the game closes
+
+	// Guards clickThrough, overlayHWND and the window's extended style. The game monitor leaves
+	// clickThrough alone until clickOverrideUntil after SetClickThrough.
+	clickMu            sync.Mutex
+	clickOverrideUntil time.Time
+}
+
+// profileServices are the services built from one profile's config. A restart stores a new
+// set instead of changing this one, so background goroutines always see a consistent set.
+type profileServices struct {
+	profile string // Active profile name
 	config  *config.Service
-	cache   *cache.Service
 	auth    *auth.Service
 	overlay *overlay.Service
 	spotify *spotify.Service
 	lyrics  *lyrics.Service
+	history *history.Service
+	// Local lyrics match log; records only while Config.Telemetry is on
+	telemetry *telemetry.Service
+}
 
-	// Windows-specific: manage click-through state for overlay during games
-	overlayHWND      uintptr
-	clickThrough     bool
-	stopClickMonitor chan struct{}
+// services returns the active profile's services; the fields are nil until OnStartup has run
+func (a *App) services() *profileServices {
+	if svc := a.active.Load(); svc != nil {
+		return svc
+	}
+	return &profileServices{}
 }
 
+// errNoRuntime is returned by window and clipboard methods called before OnStartup has run
+var errNoRuntime = errors.New("context not available: the app is still starting up")
+
 // NewApp creates a new App application struct
 func NewApp() *App {
 	return &App{}
@@ -53,55 +109,211 @@ func NewApp() *App {
 func (a *App) OnStartup(ctx context.Context) {
 	a.ctx = ctx
 
-	// Initialize config service
-	configSvc, err := config.New()
-	if err != nil {
-		fmt.Printf("Failed to initialize config: %v\n", err)
-		os.Exit(1)
+	// Initialize config service (reuse the one preloaded in main, if any)
+	configSvc := a.services().config
+	if configSvc == nil {
+		var err error
+		configSvc, err = config.New()
+		if err != nil {
+			log.Printf("Failed to initialize config: %v", err)
+			os.Exit(1)
+		}
+	}
+	a.active.Store(&profileServices{profile: config.DefaultProfile, config: configSvc})
+
+	// Initialize cache service (shared across profiles)
+	a.cache = cache.New(100) // 100 entry cache
+	a.pinnedPath = filepath.Join(filepath.Dir(configSvc.Path()), "pinned_lyrics.json")
+	if err := a.cache.LoadPinned(a.pinnedPath); err != nil {
+		log.Printf("Failed to load pinned lyrics: %v", err)
+	}
+
+	a.startCachePruner()
+
+	if err := a.startProfileServices(config.DefaultProfile, configSvc); err != nil {
+		log.Printf("Failed to start services: %v", err)
+	}
+
+	// Monitors may have changed since the last run; don't restore the overlay off-screen
+	if _, err := a.EnsureOnScreen(); err != nil {
+		log.Printf("Failed to check overlay position: %v", err)
+	}
+
+	// Preload the offline lyrics library, if the user has one
+	datasetPath := filepath.Join(filepath.Dir(configSvc.Path()), "lyrics_dataset.json")
+	if _, err := os.Stat(datasetPath); err == nil {
+		if _, err := a.LoadDataset(datasetPath); err != nil {
+			log.Printf("Failed to load lyrics dataset: %v", err)
+		}
 	}
-	a.config = configSvc
 
-	// Initialize cache service
-	cacheSvc := cache.New(100) // 100 entry cache
-	a.cache = cacheSvc
+	// Start background monitor to toggle click-through during games (e.g., VALORANT)
+	a.startClickThroughMonitor()
+}
+
+// startProfileServices creates the services that depend on the profile's config and makes
+// them the active ones. If the overlay can't be created, only the config is left active.
+func (a *App) startProfileServices(profile string, configSvc *config.Service) error {
+	cacheSvc := a.cache
+	svc := &profileServices{profile: profile, config: configSvc}
 
 	// Initialize overlay service
 	overlaySvc, err := overlay.New(configSvc)
 	if err != nil {
-		fmt.Printf("Failed to initialize overlay: %v\n", err)
-		os.Exit(1)
+		a.active.Store(svc)
+		return fmt.Errorf("failed to initialize overlay: %w", err)
 	}
-	a.overlay = overlaySvc
+	overlaySvc.SetContext(a.ctx)
+	svc.overlay = overlaySvc
 
 	// Initialize auth service
 	authSvc, err := auth.New(configSvc)
 	if err != nil {
-		fmt.Printf("Failed to initialize auth: %v\n", err)
+		log.Printf("Failed to initialize auth: %v", err)
 		// Don't exit, we can still show the UI for authentication
 	}
-	a.auth = authSvc
+	svc.auth = authSvc
 
 	// Initialize lyrics service
-	lyricsSvc := lyrics.New(cacheSvc)
-	a.lyrics = lyricsSvc
+	lyrics.SetContact(configSvc.Get().ProviderContact)
+	lrclibBaseURL := configSvc.Get().LRCLibBaseURL
+	if lrclibBaseURL != "" {
+		if err := lyrics.ValidateBaseURL(lrclibBaseURL); err != nil {
+			log.Printf("Ignoring lrclib_base_url, using %s: %v", lyrics.DefaultLRCLibBaseURL, err)
+			lrclibBaseURL = ""
+		}
+	}
+	lyricsSvc := lyrics.New(cacheSvc, lrclibBaseURL, nil)
+	for name, timeoutMs := range configSvc.Get().ProviderTimeouts {
+		lyricsSvc.SetProviderTimeout(name, time.Duration(timeoutMs)*time.Millisecond)
+	}
+	lyricsSvc.SetTotalTimeout(time.Duration(configSvc.Get().LyricsLookupTimeout) * time.Millisecond)
+	lyricsSvc.SetMaxConcurrentFetches(configSvc.Get().MaxConcurrentFetches)
+	lyricsSvc.SetPreferPlainOnLowConfidence(configSvc.Get().PreferPlainOnLowConfidence)
+	for _, name := range configSvc.Get().DisabledProviders {
+		if _, err := lyricsSvc.SetProviderEnabled(name, false); err != nil {
+			log.Printf("Ignoring disabled provider: %v", err)
+		}
+	}
+	svc.lyrics = lyricsSvc
+
+	// Initialize match telemetry (stored next to the profile's config)
+	telemetrySvc, err := telemetry.New(filepath.Join(filepath.Dir(configSvc.Path()), "match_log.json"))
+	if err != nil {
+		log.Printf("Failed to initialize telemetry: %v", err)
+	} else {
+		svc.telemetry = telemetrySvc
+		lyricsSvc.SetLookupObserver(func(query lyrics.TrackQuery, data *overlay.LyricsData, err error) {
+			a.recordMatch(configSvc, telemetrySvc, query, data, err)
+		})
+	}
+
+	// Initialize play history
+	historySvc := history.New(configSvc.Get().HistorySize)
+	svc.history = historySvc
 
 	// Initialize Spotify service
 	if authSvc != nil {
-		spotifySvc := spotify.New(authSvc, overlaySvc, lyricsSvc)
-		a.spotify = spotifySvc
+		lyricsSvc.SetCountrySource(authSvc.Country)
+		spotifySvc := spotify.New(authSvc, overlaySvc, lyricsSvc, historySvc)
+		spotifySvc.SetContext(a.ctx)
+		svc.spotify = spotifySvc
+	}
+	a.active.Store(svc)
 
-		// Start polling if authenticated
-		if authSvc.IsAuthenticated() {
-			spotifySvc.Start()
-		}
+	// Start polling if authenticated
+	if svc.spotify != nil && authSvc.IsAuthenticated() {
+		svc.spotify.Start()
 	}
+	return nil
+}
 
-	// Start background monitor to toggle click-through during games (e.g., VALORANT)
-	a.startClickThroughMonitor()
+// stopProfileServices stops the active profile's services and saves its config
+func (a *App) stopProfileServices() {
+	a.StopDemoPlayback()
+	a.StopExternalControl()
+	a.cancelOpacityAnimation()
+	svc := a.services()
+	if svc.spotify != nil {
+		svc.spotify.Stop()
+	}
+	if svc.auth != nil {
+		svc.auth.Close()
+	}
+	if svc.overlay != nil {
+		svc.overlay.Shutdown()
+	}
+	if svc.telemetry != nil {
+		_ = svc.telemetry.Flush()
+	}
+	if svc.config != nil {
+		_ = svc.config.Save()
+	}
+}
+
+// ListProfiles returns the available profiles, starting with the default one
+func (a *App) ListProfiles() ([]string, error) {
+	return config.ListProfiles()
+}
+
+// GetCurrentProfile returns the name of the active profile
+func (a *App) GetCurrentProfile() string {
+	svc := a.services()
+	return svc.profile
+}
+
+// CreateProfile creates a new profile using the current Spotify app credentials
+func (a *App) CreateProfile(name string) error {
+	svc := a.services()
+	if config.ProfileExists(name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	profileConfig, err := config.NewForProfile(name)
+	if err != nil {
+		return err
+	}
+
+	// Client credentials belong to the Spotify app, not the account, so carry them over
+	if svc.config != nil {
+		current := svc.config.Get()
+		cfg := profileConfig.Get()
+		cfg.SpotifyClientID = current.SpotifyClientID
+		cfg.SpotifyClientSecret = current.SpotifyClientSecret
+		cfg.RedirectURI = current.RedirectURI
+		cfg.Port = current.Port
+	}
+	return profileConfig.Save()
+}
+
+// SwitchProfile tears down the current services and restarts them with the named profile
+func (a *App) SwitchProfile(name string) error {
+	a.restartMu.Lock()
+	defer a.restartMu.Unlock()
+	if name == a.services().profile {
+		return nil
+	}
+	if !config.ProfileExists(name) {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+	profileConfig, err := config.NewForProfile(name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile %q: %w", name, err)
+	}
+
+	a.stopProfileServices()
+	if err := a.startProfileServices(name, profileConfig); err != nil {
+		return err
+	}
+
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "profile:switched", name)
+	}
+	return nil
 }
 
 // OnShutdown is called when the app is shutting down
 func (a *App) OnShutdown(ctx context.Context) {
+	svc := a.services()
 	// Stop click-through monitor if running
 	if a.stopClickMonitor != nil {
 		select {
@@ -112,35 +324,54 @@ func (a *App) OnShutdown(ctx context.Context) {
 		}
 	}
 
-	if a.spotify != nil {
-		a.spotify.Stop()
+	if a.stopCachePruner != nil {
+		close(a.stopCachePruner)
+		a.stopCachePruner = nil
+	}
+
+	if svc.spotify != nil {
+		svc.spotify.Stop()
+	}
+	if svc.auth != nil {
+		svc.auth.Logout()
 	}
-	if a.auth != nil {
-		a.auth.Logout()
+	if svc.overlay != nil {
+		svc.overlay.Shutdown()
 	}
-	if a.overlay != nil {
-		a.overlay.Shutdown()
+	if svc.telemetry != nil {
+		_ = svc.telemetry.Flush()
 	}
-	if a.config != nil {
-		a.config.Save()
+	if svc.config != nil {
+		svc.config.Save()
 	}
 }
 
+// onSecondInstanceLaunch brings the running overlay to the front when the app is launched again
+func (a *App) onSecondInstanceLaunch(data options.SecondInstanceData) {
+	if a.ctx == nil {
+		return
+	}
+	runtime.WindowUnminimise(a.ctx)
+	runtime.WindowShow(a.ctx)
+}
+
 // IsAuthenticated checks if user is authenticated with Spotify
 func (a *App) IsAuthenticated() bool {
-	if a.auth == nil {
+	svc := a.services()
+	if svc.auth == nil {
 		return false
 	}
-	return a.auth.IsAuthenticated()
+	return svc.auth.IsAuthenticated()
 }
 
 // StartOAuthFlow starts the Spotify OAuth flow
 func (a *App) StartOAuthFlow() error {
-	if a.auth == nil {
+	svc := a.services()
+	if svc.auth == nil {
 		return fmt.Errorf("auth service not initialized - check that Spotify credentials are configured in ~/.spotly/config.json")
 	}
 
-	err := a.auth.StartOAuthFlow()
+	err := svc.auth.StartOAuthFlow()
 	if err != nil {
 		return fmt.Errorf("failed to start OAuth flow: %w", err)
 	}
@@ -148,11 +379,61 @@ func (a *App) StartOAuthFlow() error {
 	return nil
 }
 
+// GetGrantedScopes returns the OAuth scopes granted to the stored Spotify token
+func (a *App) GetGrantedScopes() []string {
+	svc := a.services()
+	if svc.auth == nil {
+		return []string{}
+	}
+	return svc.auth.GetGrantedScopes()
+}
+
+// NeedsReauth reports whether the user must reconnect Spotify to grant new permissions
+func (a *App) NeedsReauth() bool {
+	svc := a.services()
+	if svc.auth == nil {
+		return false
+	}
+	return svc.auth.NeedsReauth()
+}
+
+// TokenExpiresIn returns how long the Spotify access token remains valid
+func (a *App) TokenExpiresIn() time.Duration {
+	svc := a.services()
+	if svc.auth == nil {
+		return 0
+	}
+	return svc.auth.TokenExpiresIn()
+}
+
+// GetUpNext returns the next track in the Spotify queue for a "next:" hint, or an empty
+// result when it isn't available
+func (a *App) GetUpNext() spotify.UpNext {
+	svc := a.services()
+	if svc.spotify == nil {
+		return spotify.UpNext{}
+	}
+	return svc.spotify.GetUpNext()
+}
+
+// GetCountry returns the Spotify account's country code, or "" when unknown
+func (a *App) GetCountry() string {
+	svc := a.services()
+	if svc.auth == nil {
+		return ""
+	}
+	return svc.auth.Country()
+}
+
 // StartSpotifyPolling manually starts Spotify polling (for use after auth)
 func (a *App) StartSpotifyPolling() bool {
-	if a.spotify != nil && a.auth != nil && a.auth.IsAuthenticated() {
-		if !a.spotify.IsPolling() {
-			a.spotify.Start()
+	svc := a.services()
+	if a.IsExternalControl() {
+		return false // Resumed by StopExternalControl
+	}
+	if svc.spotify != nil && svc.auth != nil && svc.auth.IsAuthenticated() {
+		if !svc.spotify.IsPolling() {
+			svc.spotify.Start()
 			return true
 		}
 	}
@@ -161,27 +442,48 @@ func (a *App) StartSpotifyPolling() bool {
 
 // GetAuthURL returns the OAuth URL for manual authentication
 func (a *App) GetAuthURL() (string, error) {
-	if a.auth == nil {
+	svc := a.services()
+	if svc.auth == nil {
 		return "", fmt.Errorf("auth service not initialized - check that Spotify credentials are configured")
 	}
-	return a.auth.GetAuthURL(), nil
+	return svc.auth.GetAuthURL(), nil
 }
 
-// GetDisplayInfo returns current lyrics display information
+// GetDisplayInfo returns current lyrics display information; kept for existing callers, see
+// GetDisplaySnapshot
 func (a *App) GetDisplayInfo() *overlay.DisplayInfo {
-	if a.overlay == nil {
-		return &overlay.DisplayInfo{
-			CurrentLine: "Service not available",
-			NextLine:    "",
-			IsPlaying:   false,
+	return &a.GetDisplaySnapshot().DisplayInfo
+}
+
+// GetDisplaySnapshot returns everything the overlay shows in one serializable struct: the track
+// header, previous/current/next lines, source, confidence, line progress and timestamps
+func (a *App) GetDisplaySnapshot() *overlay.DisplaySnapshot {
+	svc := a.services()
+	if svc.overlay == nil {
+		return &overlay.DisplaySnapshot{
+			DisplayInfo: overlay.DisplayInfo{
+				CurrentLine: "Service not available",
+				NextLine:    "",
+				IsPlaying:   false,
+			},
+			CapturedAt: time.Now(),
 		}
 	}
 
-	info := a.overlay.GetDisplayInfo()
+	snapshot := svc.overlay.GetDisplaySnapshot()
+	info := &snapshot.DisplayInfo
+
+	// Prompt re-authentication when the token is missing required scopes
+	if svc.auth != nil && svc.auth.NeedsReauth() {
+		info.CurrentLine = "🔑 Spotify permissions need updating"
+		info.NextLine = "Reconnect with Spotify to continue"
+		snapshot.PreviousLine = ""
+		return snapshot
+	}
 
 	// Add debugging info if no track is playing
-	if info.CurrentLine == "No track playing" && a.auth != nil && a.auth.IsAuthenticated() {
-		if a.spotify != nil && a.spotify.IsPolling() {
+	if info.CurrentLine == "No track playing" && svc.auth != nil && svc.auth.IsAuthenticated() {
+		if svc.spotify != nil && svc.spotify.IsPolling() {
 			info.CurrentLine = "🎧 Ready and waiting"
 			info.NextLine = "Start playing music in Spotify"
 		} else {
@@ -190,11 +492,12 @@ func (a *App) GetDisplayInfo() *overlay.DisplayInfo {
 		}
 	}
 
-	return info
+	return snapshot
 }
 
 // GetSpotifyStatus returns debug info about Spotify connection
 func (a *App) GetSpotifyStatus() map[string]interface{} {
+	svc := a.services()
 	status := map[string]interface{}{
 		"authenticated": false,
 		"polling":       false,
@@ -202,23 +505,25 @@ func (a *App) GetSpotifyStatus() map[string]interface{} {
 		"current_track": nil,
 	}
 
-	if a.auth != nil {
-		status["authenticated"] = a.auth.IsAuthenticated()
-		status["has_client"] = a.auth.GetClient() != nil
+	if svc.auth != nil {
+		status["authenticated"] = svc.auth.IsAuthenticated()
+		status["has_client"] = svc.auth.GetClient() != nil
 	}
 
-	if a.spotify != nil {
-		status["polling"] = a.spotify.IsPolling()
+	if svc.spotify != nil {
+		status["polling"] = svc.spotify.IsPolling()
+		status["device"] = svc.spotify.GetActiveDevice()
 	}
 
-	if a.overlay != nil {
-		currentTrack := a.overlay.GetCurrentTrack()
+	if svc.overlay != nil {
+		currentTrack := svc.overlay.GetCurrentTrack()
 		if currentTrack != nil {
 			status["current_track"] = map[string]interface{}{
-				"name":    currentTrack.Name,
-				"artists": currentTrack.Artists,
-				"playing": currentTrack.IsPlaying,
-				"id":      currentTrack.ID,
+				"name":     currentTrack.Name,
+				"artists":  currentTrack.Artists,
+				"playing":  currentTrack.IsPlaying,
+				"id":       currentTrack.ID,
+				"explicit": currentTrack.Explicit,
 			}
 		}
 	}
@@ -226,17 +531,85 @@ func (a *App) GetSpotifyStatus() map[string]interface{} {
 	return status
 }
 
+// GetCurrentLyricsSource reports which provider produced the lyrics on screen and whether
+// they came from the cache
+func (a *App) GetCurrentLyricsSource() map[string]interface{} {
+	svc := a.services()
+	source := map[string]interface{}{
+		"source": "",
+		"cached": false,
+	}
+	if svc.overlay == nil {
+		return source
+	}
+	if current := svc.overlay.GetCurrentLyrics(); current != nil {
+		source["source"] = current.Source
+		source["cached"] = current.FromCache
+	}
+	return source
+}
+
+// GetSystemHealth reports the state of the app's services, including lyrics provider health
+func (a *App) GetSystemHealth() map[string]interface{} {
+	svc := a.services()
+	health := map[string]interface{}{
+		"authenticated":    svc.auth != nil && svc.auth.IsAuthenticated(),
+		"spotify_polling":  svc.spotify != nil && svc.spotify.IsPolling(),
+		"lyrics_providers": []lyrics.ProviderHealth{},
+	}
+	if svc.lyrics != nil {
+		health["lyrics_providers"] = svc.lyrics.ProviderHealth()
+	}
+	return health
+}
+
+// GetProviders lists the lyrics providers in lookup order with their enabled state
+func (a *App) GetProviders() []lyrics.ProviderInfo {
+	svc := a.services()
+	if svc.lyrics == nil {
+		return []lyrics.ProviderInfo{}
+	}
+	return svc.lyrics.Providers()
+}
+
+// SetProviderEnabled turns a lyrics provider on or off (including the Demo fallback) and
+// saves the choice. It applies from the next lookup.
+func (a *App) SetProviderEnabled(name string, enabled bool) error {
+	svc := a.services()
+	if svc.lyrics == nil {
+		return fmt.Errorf("lyrics service not available")
+	}
+	name, err := svc.lyrics.SetProviderEnabled(name, enabled)
+	if err != nil {
+		return err
+	}
+
+	cfg := svc.config.Get()
+	disabled := make([]string, 0, len(cfg.DisabledProviders)+1)
+	for _, existing := range cfg.DisabledProviders {
+		if !strings.EqualFold(existing, name) {
+			disabled = append(disabled, existing)
+		}
+	}
+	if !enabled {
+		disabled = append(disabled, name)
+	}
+	cfg.DisabledProviders = disabled
+	return svc.config.Save()
+}
+
 // TestSpotifyConnection manually tests the Spotify API connection
 func (a *App) TestSpotifyConnection() string {
-	if a.auth == nil {
+	svc := a.services()
+	if svc.auth == nil {
 		return "❌ Auth service not available"
 	}
 
-	if !a.auth.IsAuthenticated() {
+	if !svc.auth.IsAuthenticated() {
 		return "❌ Not authenticated"
 	}
 
-	client := a.auth.GetClient()
+	client := svc.auth.GetClient()
 	if client == nil {
 		return "❌ No Spotify client"
 	}
@@ -258,21 +631,22 @@ func (a *App) TestSpotifyConnection() string {
 		return "⚠️ No track item (ads or podcast?)"
 	}
 
-	return fmt.Sprintf("✅ Found: %s by %s", playerState.Item.Name, playerState.Item.Artists[0].Name)
+	return fmt.Sprintf("✅ Found: %s by %s", playerState.Item.Name, spotify.ArtistNames(playerState.Item.Artists)[0])
 }
 
 // RefreshNow forces an immediate Spotify poll and lyrics fetch
 func (a *App) RefreshNow() string {
-	if a.spotify == nil {
+	svc := a.services()
+	if svc.spotify == nil {
 		return "❌ Spotify service not available"
 	}
 
-	if a.auth == nil || !a.auth.IsAuthenticated() {
+	if svc.auth == nil || !svc.auth.IsAuthenticated() {
 		return "❌ Not authenticated"
 	}
 
 	// Force a poll
-	client := a.auth.GetClient()
+	client := svc.auth.GetClient()
 	if client == nil {
 		return "❌ No Spotify client"
 	}
@@ -280,13 +654,15 @@ func (a *App) RefreshNow() string {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	requestStart := time.Now()
 	playerState, err := client.PlayerCurrentlyPlaying(ctx)
+	roundTrip := time.Since(requestStart)
 	if err != nil {
 		return fmt.Sprintf("❌ API Error: %v", err)
 	}
 
 	if playerState == nil || playerState.Item == nil {
-		a.overlay.SetCurrentTrack(nil)
+		svc.overlay.SetCurrentTrack(nil)
 		return "⚠️ No active playback"
 	}
 
@@ -294,44 +670,402 @@ func (a *App) RefreshNow() string {
 	track := &overlay.TrackInfo{
 		ID:        playerState.Item.ID.String(),
 		Name:      playerState.Item.Name,
-		Artists:   []string{playerState.Item.Artists[0].Name},
+		Artists:   spotify.ArtistNames(playerState.Item.Artists),
 		Album:     playerState.Item.Album.Name,
 		Duration:  int64(playerState.Item.Duration),
 		Progress:  int64(playerState.Progress),
 		IsPlaying: playerState.Playing,
 		UpdatedAt: time.Now(),
+		ISRC:      playerState.Item.ExternalIDs["isrc"],
+		Explicit:  playerState.Item.Explicit,
+	}
+	spotify.CorrectForLatency(track, roundTrip)
+
+	svc.overlay.SetCurrentTrack(track)
+
+	// Fetch lyrics the same way the poll loop does, dropping them if the track changes meanwhile
+	svc.spotify.FetchLyrics(track)
+
+	return fmt.Sprintf("✅ Refreshed: %s by %s", track.Name, track.Artists[0])
+}
+
+// LookupLyrics runs the lyrics provider chain for an artist and title without touching the
+// overlay or the playing track
+func (a *App) LookupLyrics(artist, title string) (*overlay.LyricsData, error) {
+	svc := a.services()
+	if svc.lyrics == nil {
+		return nil, fmt.Errorf("lyrics service not available")
+	}
+	if strings.TrimSpace(artist) == "" || strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("artist and title are required")
+	}
+	return svc.lyrics.SearchLyrics(context.Background(), artist, title)
+}
+
+// ListLyricsCandidates returns the LRCLIB matches for the current track, for picking the right
+// lyrics when the automatic match is wrong
+func (a *App) ListLyricsCandidates() ([]lyrics.LyricsCandidate, error) {
+	svc := a.services()
+	if svc.lyrics == nil || svc.overlay == nil {
+		return nil, fmt.Errorf("lyrics service not available")
+	}
+	track := svc.overlay.GetCurrentTrack()
+	if track == nil {
+		return nil, fmt.Errorf("no track playing")
+	}
+	artist := ""
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0]
+	}
+	return svc.lyrics.ListCandidates(context.Background(), artist, track.Name)
+}
+
+// SelectLyricsCandidate shows the LRCLIB record with the given ID for the current track and
+// caches it in place of the automatic match
+func (a *App) SelectLyricsCandidate(id int) error {
+	svc := a.services()
+	if svc.lyrics == nil || svc.overlay == nil {
+		return fmt.Errorf("lyrics service not available")
+	}
+	track := svc.overlay.GetCurrentTrack()
+	if track == nil {
+		return fmt.Errorf("no track playing")
+	}
+	data, err := svc.lyrics.SelectCandidate(context.Background(), track, id)
+	if err != nil {
+		return err
 	}
+	if !svc.overlay.SetLyricsForTrack(track.ID, data) {
+		return fmt.Errorf("track changed while fetching the selected lyrics")
+	}
+	a.markMatchCorrected(track.ID)
+	return nil
+}
+
+// GetLyricsMetadata describes the record the current lyrics were matched to (album, length,
+// match kind), with a note when the album differs from the playing track's
+func (a *App) GetLyricsMetadata() (*lyrics.LyricsMetadata, error) {
+	svc := a.services()
+	if svc.overlay == nil {
+		return nil, fmt.Errorf("overlay service not available")
+	}
+	data := svc.overlay.GetCurrentLyrics()
+	if data == nil {
+		return nil, fmt.Errorf("no lyrics loaded")
+	}
+	meta := lyrics.Metadata(data, svc.overlay.GetCurrentTrack())
+	return &meta, nil
+}
+
+// GetPendingLyrics returns the low-confidence match awaiting confirmation, or nil
+func (a *App) GetPendingLyrics() *overlay.LyricsData {
+	svc := a.services()
+	if svc.overlay == nil {
+		return nil
+	}
+	return svc.overlay.PendingLyrics()
+}
+
+// ConfirmLyrics shows the low-confidence match held back by Config.AutoApplyConfidence
+func (a *App) ConfirmLyrics() error {
+	svc := a.services()
+	if svc.overlay == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+	if !svc.overlay.ConfirmLyrics() {
+		return fmt.Errorf("no lyrics awaiting confirmation")
+	}
+	return nil
+}
+
+// HasLyrics reports whether lyrics are available for a track without showing them, e.g. to
+// badge songs in a list. Unknown tracks are looked up (and cached); recent misses are not.
+func (a *App) HasLyrics(trackID, artist, title string) bool {
+	svc := a.services()
+	if svc.lyrics == nil {
+		return false
+	}
+	return svc.lyrics.HasLyrics(context.Background(), trackID, artist, title, true)
+}
+
+// GetPlayHistory returns recently played tracks, most recent first
+func (a *App) GetPlayHistory() []history.Entry {
+	svc := a.services()
+	if svc.history == nil {
+		return []history.Entry{}
+	}
+	return svc.history.Entries()
+}
+
+// ShowHistoryLyrics displays the cached lyrics of a previously played track in read-only mode
+func (a *App) ShowHistoryLyrics(trackID string) error {
+	svc := a.services()
+	if svc.overlay == nil || svc.history == nil || a.cache == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+
+	if _, ok := svc.history.Get(trackID); !ok {
+		return fmt.Errorf("track %s is not in play history", trackID)
+	}
+
+	lyrics := a.cache.GetByTrackID(trackID)
+	if lyrics == nil {
+		return fmt.Errorf("no cached lyrics for track %s", trackID)
+	}
+
+	svc.overlay.ShowReadOnlyLyrics(lyrics)
+	return nil
+}
+
+// CloseHistoryLyrics leaves read-only mode and resumes showing the playing track
+func (a *App) CloseHistoryLyrics() {
+	svc := a.services()
+	if svc.overlay == nil {
+		return
+	}
+	svc.overlay.ClearReadOnlyLyrics()
+}
 
-	a.overlay.SetCurrentTrack(track)
+// ListCacheEntries returns a snapshot of the lyrics cache for the debug panel
+func (a *App) ListCacheEntries() []cache.CacheEntryInfo {
+	if a.cache == nil {
+		return []cache.CacheEntryInfo{}
+	}
+	return a.cache.ListEntries()
+}
+
+// PruneCache drops expired lyrics cache entries, enforces the size budget and rewrites the
+// pinned lyrics file. Also runs on startup and every Config.CachePruneIntervalHours.
+func (a *App) PruneCache() cache.CacheStats {
+	if a.cache == nil {
+		return cache.CacheStats{}
+	}
+	pruned := a.cache.Prune()
+	stats := a.cache.Stats()
+	stats.Pruned = pruned
+	if stats.Pinned > 0 {
+		if err := a.cache.SavePinned(a.pinnedPath); err != nil {
+			log.Printf("Failed to save pinned lyrics: %v", err)
+		}
+	}
+	return stats
+}
 
-	// Try to fetch lyrics if we have the lyrics service
-	if a.lyrics != nil {
-		go func() {
-			lyrics, err := a.lyrics.GetLyrics(track.ID, track.Artists[0], track.Name)
-			if err == nil && lyrics != nil {
-				a.overlay.SetCurrentLyrics(lyrics)
-			} else {
-				// If lyrics failed, clear any old lyrics
-				a.overlay.SetCurrentLyrics(nil)
+// startCachePruner prunes the cache now and then periodically until OnShutdown
+func (a *App) startCachePruner() {
+	svc := a.services()
+	if a.stopCachePruner != nil {
+		return // already running
+	}
+	interval := cache.DefaultPruneInterval
+	if hours := svc.config.Get().CachePruneIntervalHours; hours > 0 {
+		interval = time.Duration(hours) * time.Hour
+	}
+	stop := make(chan struct{})
+	a.stopCachePruner = stop
+
+	go func() {
+		a.PruneCache()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.PruneCache()
+			case <-stop:
+				return
 			}
-		}()
+		}
+	}()
+}
+
+// GetCacheKeyForCurrentTrack returns the normalized cache key of the playing track, to explain
+// why two tracks can share lyrics
+func (a *App) GetCacheKeyForCurrentTrack() string {
+	svc := a.services()
+	if svc.overlay == nil {
+		return ""
+	}
+	track := svc.overlay.GetCurrentTrack()
+	if track == nil {
+		return ""
 	}
+	artist := ""
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0]
+	}
+	return lyrics.NormalizeCacheKey(artist, track.Name)
+}
 
-	return fmt.Sprintf("✅ Refreshed: %s by %s", track.Name, track.Artists[0])
+// DeleteCacheEntry evicts the cached lyrics for a single track
+func (a *App) DeleteCacheEntry(trackID string) error {
+	if a.cache == nil {
+		return fmt.Errorf("cache service not available")
+	}
+	pinned := a.cache.IsPinned(trackID)
+	if !a.cache.RemoveByTrackID(trackID) {
+		return fmt.Errorf("no cache entry for track %s", trackID)
+	}
+	a.markMatchCorrected(trackID)
+	if pinned {
+		return a.cache.SavePinned(a.pinnedPath)
+	}
+	return nil
+}
+
+// LoadDataset preloads a JSON dataset of lyrics into the cache for offline use,
+// returning how many songs were loaded
+func (a *App) LoadDataset(path string) (int, error) {
+	svc := a.services()
+	if svc.lyrics == nil {
+		return 0, fmt.Errorf("lyrics service not available")
+	}
+	return svc.lyrics.LoadDataset(path)
+}
+
+// PinCurrentLyrics keeps the playing track's lyrics in the cache permanently, e.g. after correcting them
+func (a *App) PinCurrentLyrics() error {
+	svc := a.services()
+	if svc.overlay == nil || a.cache == nil {
+		return fmt.Errorf("cache service not available")
+	}
+	track := svc.overlay.GetCurrentTrack()
+	data := svc.overlay.GetCurrentLyrics()
+	if track == nil || data == nil || data.TrackID != track.ID || !data.HasLyrics() {
+		return fmt.Errorf("no lyrics to pin")
+	}
+
+	if a.cache.GetByTrackID(track.ID) == nil {
+		a.cache.SetByTrackID(track.ID, data)
+	}
+	if err := a.cache.Pin(track.ID); err != nil {
+		return err
+	}
+	a.markMatchCorrected(track.ID)
+	return a.cache.SavePinned(a.pinnedPath)
+}
+
+// UnpinLyrics lets a track's pinned lyrics be evicted from the cache again
+func (a *App) UnpinLyrics(trackID string) error {
+	if a.cache == nil {
+		return fmt.Errorf("cache service not available")
+	}
+	if !a.cache.Unpin(trackID) {
+		return fmt.Errorf("lyrics for track %s are not pinned", trackID)
+	}
+	return a.cache.SavePinned(a.pinnedPath)
+}
+
+// recordMatch logs a provider lookup when telemetry is enabled
+func (a *App) recordMatch(configSvc *config.Service, telemetrySvc *telemetry.Service, query lyrics.TrackQuery, data *overlay.LyricsData, err error) {
+	if !configSvc.Get().Telemetry {
+		return
+	}
+
+	record := telemetry.Record{
+		TrackKey:   telemetry.TrackKey(query.TrackID),
+		Artist:     lyrics.NormalizeTitle(query.Artist),
+		Title:      lyrics.NormalizeTitle(query.Title),
+		DurationMs: query.DurationMs,
+	}
+	if data != nil {
+		record.Provider = data.Source
+		record.MatchConfidence = data.MatchConfidence
+		record.Synced = data.IsSynced
+		record.Found = err == nil
+	}
+	if err := telemetrySvc.Record(record); err != nil {
+		log.Printf("Failed to record lyrics match: %v", err)
+	}
+}
+
+// markMatchCorrected flags the track's last lookup as corrected by the user
+func (a *App) markMatchCorrected(trackID string) {
+	svc := a.services()
+	if svc.telemetry == nil || svc.config == nil || !svc.config.Get().Telemetry {
+		return
+	}
+	if err := svc.telemetry.MarkCorrected(trackID); err != nil {
+		log.Printf("Failed to update match log: %v", err)
+	}
+}
+
+// ExportMatchReport writes the anonymized lyrics match log to path
+func (a *App) ExportMatchReport(path string) error {
+	svc := a.services()
+	if svc.telemetry == nil {
+		return fmt.Errorf("telemetry service not available")
+	}
+	if err := svc.telemetry.ExportReport(path); err != nil {
+		return fmt.Errorf("failed to export match report: %w", err)
+	}
+	return nil
+}
+
+// GetShareText returns a shareable "now playing" message with the current lyrics line
+func (a *App) GetShareText() (string, error) {
+	svc := a.services()
+	if svc.overlay == nil {
+		return "", fmt.Errorf("overlay service not available")
+	}
+	return svc.overlay.ShareText()
+}
+
+// CopyShareText copies the shareable "now playing" message to the clipboard
+func (a *App) CopyShareText() error {
+	text, err := a.GetShareText()
+	if err != nil {
+		return err
+	}
+	if a.ctx == nil {
+		return errNoRuntime
+	}
+	if err := runtime.ClipboardSetText(a.ctx, text); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return nil
+}
+
+// GetSyncQuality reports whether lyrics appear to run early or late and how to adjust the sync offset
+func (a *App) GetSyncQuality() overlay.SyncQuality {
+	svc := a.services()
+	if svc.overlay == nil {
+		return overlay.SyncQuality{}
+	}
+	return svc.overlay.GetSyncQuality()
 }
 
 // ToggleVisibility toggles overlay visibility
 func (a *App) ToggleVisibility() bool {
-	if a.overlay == nil {
+	svc := a.services()
+	if svc.overlay == nil {
 		return false
 	}
-	return a.overlay.ToggleVisibility()
+	return svc.overlay.ToggleVisibility()
+}
+
+// FreezeDisplay holds the lyrics on the current line so it can be read while playback continues
+func (a *App) FreezeDisplay() bool {
+	svc := a.services()
+	if svc.overlay == nil {
+		return false
+	}
+	return svc.overlay.FreezeDisplay()
+}
+
+// UnfreezeDisplay resumes lyrics in sync with playback
+func (a *App) UnfreezeDisplay() {
+	svc := a.services()
+	if svc.overlay != nil {
+		svc.overlay.UnfreezeDisplay()
+	}
 }
 
 // ResizeWindow resizes the overlay window with smooth transition
 func (a *App) ResizeWindow(width, height int) error {
 	if a.ctx == nil {
-		return fmt.Errorf("context not available")
+		return errNoRuntime
 	}
 
 	// Get current window position to maintain center point
@@ -354,16 +1088,158 @@ func (a *App) ResizeWindow(width, height int) error {
 	return nil
 }
 
+// FitOverlayToLyrics resizes the overlay so the displayed lyric lines fit on one line,
+// keeping it centered and within the current screen (requires Overlay.AutoFit)
+func (a *App) FitOverlayToLyrics() error {
+	svc := a.services()
+	if svc.overlay == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+	if a.ctx == nil {
+		return errNoRuntime
+	}
+	overlayConfig := svc.overlay.GetOverlayConfig()
+	if !overlayConfig.AutoFit {
+		return fmt.Errorf("auto-fit is disabled")
+	}
+
+	info := svc.overlay.GetDisplayInfo()
+	width := overlay.FitWidth([]string{info.CurrentLine, info.CurrentSecondary, info.NextLine}, overlayConfig.FontSize)
+
+	screenWidth := 0
+	if screens, err := runtime.ScreenGetAll(a.ctx); err == nil {
+		for _, screen := range screens {
+			if screen.IsCurrent || (screenWidth == 0 && screen.IsPrimary) {
+				screenWidth = screen.Size.Width
+			}
+		}
+	}
+	if screenWidth > 0 && width > screenWidth {
+		width = screenWidth
+	}
+
+	_, height := runtime.WindowGetSize(a.ctx)
+	if err := a.ResizeWindow(width, height); err != nil {
+		return err
+	}
+
+	// Keeping the center can push a widened window past its screen's edge; pull it back
+	if bounds, exact, err := a.screenBounds(); err == nil && exact {
+		x, y := runtime.WindowGetPosition(a.ctx)
+		runtime.WindowSetPosition(a.ctx, overlay.ClampXToScreen(x, y, width, height, bounds), y)
+	}
+	return nil
+}
+
+// EnsureOnScreen moves the overlay back to its Position corner of the primary screen if the
+// saved position or the window itself is off every screen. Returns true if it was moved. Does
+// nothing where monitor positions are unknown.
+func (a *App) EnsureOnScreen() (bool, error) {
+	svc := a.services()
+	if svc.overlay == nil {
+		return false, fmt.Errorf("overlay service not available")
+	}
+	if a.ctx == nil {
+		return false, errNoRuntime
+	}
+	bounds, exact, err := a.screenBounds()
+	if err != nil {
+		return false, err
+	}
+	if !exact {
+		return false, nil
+	}
+
+	moved, err := svc.overlay.EnsureOnScreen(bounds)
+	if err != nil {
+		return moved, err
+	}
+
+	x, y := runtime.WindowGetPosition(a.ctx)
+	width, height := runtime.WindowGetSize(a.ctx)
+	if !moved && overlay.OnScreen(x, y, width, height, bounds) {
+		return false, nil
+	}
+	if moved {
+		overlayConfig := svc.overlay.GetOverlayConfig()
+		x, y = overlayConfig.X, overlayConfig.Y
+	} else {
+		x, y = overlay.CornerPosition(svc.overlay.GetOverlayConfig().Position, width, height, bounds)
+	}
+	runtime.WindowSetPosition(a.ctx, x, y)
+	return true, nil
+}
+
+// screenBounds lists the monitors in virtual desktop coordinates (requires a.ctx). Where the
+// platform can't report monitor positions, only the primary screen is returned, at the origin,
+// and exact is false: the other screens are unknown, so no position should count as off-screen.
+func (a *App) screenBounds() (bounds []overlay.ScreenBounds, exact bool, err error) {
+	if bounds, err := monitorBounds(); err == nil {
+		return bounds, true, nil
+	}
+	screens, err := runtime.ScreenGetAll(a.ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list screens: %w", err)
+	}
+	for _, screen := range screens {
+		if screen.IsPrimary {
+			return []overlay.ScreenBounds{{Width: screen.Size.Width, Height: screen.Size.Height, Primary: true}}, false, nil
+		}
+	}
+	return nil, false, fmt.Errorf("no primary screen found")
+}
+
+// moveForGame moves the overlay to Overlay.InGamePosition when a game is detected, saving the
+// normal position to Overlay.X/Y, and moves it back when the game closes
+func (a *App) moveForGame(inGame bool) {
+	svc := a.services()
+	if svc.overlay == nil || a.ctx == nil {
+		return
+	}
+	overlayConfig := svc.overlay.GetOverlayConfig()
+
+	if !inGame {
+		if a.movedForGame {
+			runtime.WindowSetPosition(a.ctx, overlayConfig.X, overlayConfig.Y)
+			a.movedForGame = false
+		}
+		return
+	}
+	if overlayConfig.InGamePosition == "" || a.movedForGame {
+		return
+	}
+	bounds, _, err := a.screenBounds()
+	if err != nil {
+		log.Printf("Failed to move overlay for game: %v", err)
+		return
+	}
+
+	overlayConfig.X, overlayConfig.Y = runtime.WindowGetPosition(a.ctx)
+	if err := svc.overlay.UpdateOverlayConfig(overlayConfig); err != nil {
+		log.Printf("Failed to save overlay position: %v", err)
+	}
+	position := overlayConfig.InGamePosition
+	if position == overlay.PositionOpposite {
+		position = overlay.OppositeCorner(overlayConfig.Position)
+	}
+	width, height := runtime.WindowGetSize(a.ctx)
+	x, y := overlay.CornerPosition(position, width, height, bounds)
+	runtime.WindowSetPosition(a.ctx, x, y)
+	a.movedForGame = true
+}
+
 // UpdateOverlayConfig updates overlay configuration
 func (a *App) UpdateOverlayConfig(config map[string]interface{}) error {
-	if a.overlay == nil {
+	svc := a.services()
+	if svc.overlay == nil {
 		return fmt.Errorf("overlay service not available")
 	}
 
-	current := a.overlay.GetOverlayConfig()
+	current := svc.overlay.GetOverlayConfig()
 
 	// Update fields if provided
 	if opacity, ok := config["opacity"].(float64); ok {
+		a.cancelOpacityAnimation()
 		current.Opacity = opacity
 	}
 	if fontSize, ok := config["font_size"].(float64); ok {
@@ -384,39 +1260,128 @@ func (a *App) UpdateOverlayConfig(config map[string]interface{}) error {
 	if syncOffset, ok := config["sync_offset"].(float64); ok {
 		current.SyncOffset = int64(syncOffset)
 	}
+	if showProgress, ok := config["show_progress"].(bool); ok {
+		current.ShowProgress = showProgress
+	}
+	if wordTiming, ok := config["word_timing"].(bool); ok {
+		current.WordTiming = wordTiming
+	}
+	if showEmptyLines, ok := config["show_empty_lines"].(bool); ok {
+		current.ShowEmptyLines = showEmptyLines
+	}
+	if minLineDisplayMs, ok := config["min_line_display_ms"].(float64); ok {
+		current.MinLineDisplayMs = int(minLineDisplayMs)
+	}
+	if lingerSeconds, ok := config["linger_seconds"].(float64); ok {
+		current.LingerSeconds = int(lingerSeconds)
+	}
+	if maxLineChars, ok := config["max_line_chars"].(float64); ok {
+		current.MaxLineChars = int(maxLineChars)
+	}
+	if autoFit, ok := config["auto_fit"].(bool); ok {
+		current.AutoFit = autoFit
+	}
+	if smoothProgress, ok := config["smooth_progress"].(bool); ok {
+		current.SmoothProgress = smoothProgress
+	}
+	if backgroundColor, ok := config["background_color"].(string); ok {
+		current.BackgroundColor = strings.TrimSpace(backgroundColor)
+	}
+	if backgroundOpacity, ok := config["background_opacity"].(float64); ok {
+		current.BackgroundOpacity = backgroundOpacity
+	}
+	if inGamePosition, ok := config["in_game_position"].(string); ok {
+		current.InGamePosition = inGamePosition
+	}
+
+	return svc.overlay.UpdateOverlayConfig(current)
+}
+
+// SetTrackSyncOffset saves a sync offset in ms for one track, overriding the global offset;
+// 0 removes it. An empty trackID means the current track.
+func (a *App) SetTrackSyncOffset(trackID string, offsetMs int64) error {
+	svc := a.services()
+	if svc.config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	if trackID == "" && svc.overlay != nil {
+		if track := svc.overlay.GetCurrentTrack(); track != nil {
+			trackID = track.ID
+		}
+	}
+	return svc.config.SetTrackSyncOffset(trackID, offsetMs)
+}
+
+// GetTrackSyncOffset returns the saved sync offset for a track (empty for the current track),
+// or 0 if it uses the global offset
+func (a *App) GetTrackSyncOffset(trackID string) int64 {
+	svc := a.services()
+	if svc.config == nil {
+		return 0
+	}
+	if trackID == "" && svc.overlay != nil {
+		if track := svc.overlay.GetCurrentTrack(); track != nil {
+			trackID = track.ID
+		}
+	}
+	offset, _ := svc.config.TrackSyncOffset(trackID)
+	return offset
+}
 
-	return a.overlay.UpdateOverlayConfig(current)
+// CommitOverlayConfig saves pending overlay changes right away, e.g. when a slider is released
+func (a *App) CommitOverlayConfig() error {
+	svc := a.services()
+	if svc.overlay == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+	return svc.overlay.CommitOverlayConfig()
 }
 
 // GetOverlayConfig returns current overlay configuration
 func (a *App) GetOverlayConfig() config.OverlayConfig {
-	if a.overlay == nil {
+	svc := a.services()
+	if svc.overlay == nil {
 		return config.OverlayConfig{}
 	}
-	return a.overlay.GetOverlayConfig()
+	return svc.overlay.GetOverlayConfig()
 }
 
 // Quit closes the application
-func (a *App) Quit() {
+func (a *App) Quit() error {
+	if a.ctx == nil {
+		return errNoRuntime
+	}
 	runtime.Quit(a.ctx)
+	return nil
+}
+
+// GetConfigWarning returns a non-fatal config problem to show the user, or "" if none
+func (a *App) GetConfigWarning() string {
+	svc := a.services()
+	if svc.config == nil {
+		return ""
+	}
+	return svc.config.Warning()
 }
 
 // GetConfigPath returns the full path to the user's config file
 func (a *App) GetConfigPath() string {
-	if a.config == nil {
+	svc := a.services()
+	if svc.config == nil {
 		return ""
 	}
-	return a.config.Path()
+	return svc.config.Path()
 }
 
 // OpenConfig opens the user's config file location in Explorer (Windows) and returns the path
 func (a *App) OpenConfig() (string, error) {
-	if a.config == nil {
+	svc := a.services()
+	if svc.config == nil {
 		return "", fmt.Errorf("config service not available")
 	}
-	path := a.config.Path()
+	path := svc.config.Path()
 	// Best-effort: ensure the file exists on disk
-	_ = a.config.Save()
+	_ = svc.config.Save()
 	// Windows: open Explorer highlighting the config file
 	_ = exec.Command("explorer.exe", "/select,", path).Start()
 	return path, nil
@@ -424,7 +1389,11 @@ func (a *App) OpenConfig() (string, error) {
 
 // OpenConfigDirectory opens the config folder in file explorer
 func (a *App) OpenConfigDirectory() error {
-	configDir := filepath.Dir(a.config.Path())
+	svc := a.services()
+	if svc.config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	configDir := filepath.Dir(svc.config.Path())
 	var cmd *exec.Cmd
 
 	switch stdruntime.GOOS {
@@ -441,29 +1410,136 @@ func (a *App) OpenConfigDirectory() error {
 	return cmd.Start()
 }
 
+// OpenLyricsSource opens the provider page for the current lyrics in the default browser
+func (a *App) OpenLyricsSource() error {
+	svc := a.services()
+	if svc.overlay == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+	lyrics := svc.overlay.GetCurrentLyrics()
+	if lyrics == nil || lyrics.SourceURL == "" {
+		return fmt.Errorf("current lyrics have no source URL")
+	}
+
+	var cmd *exec.Cmd
+	switch stdruntime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", lyrics.SourceURL)
+	case "darwin":
+		cmd = exec.Command("open", lyrics.SourceURL)
+	case "linux":
+		cmd = exec.Command("xdg-open", lyrics.SourceURL)
+	default:
+		return fmt.Errorf("unsupported platform")
+	}
+
+	return cmd.Start()
+}
+
 // SaveSpotifyCredentials saves credentials from the UI
 func (a *App) SaveSpotifyCredentials(clientID, clientSecret string) error {
 	if clientID == "" || clientSecret == "" {
 		return fmt.Errorf("client ID and secret are required")
 	}
 
-	cfg := a.config.Get()
+	a.restartMu.Lock()
+	defer a.restartMu.Unlock()
+	svc := a.services()
+	if svc.config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	cfg := svc.config.Get()
 	cfg.SpotifyClientID = clientID
 	cfg.SpotifyClientSecret = clientSecret
-	cfg.RedirectURI = "http://127.0.0.1:8080/callback"
-	cfg.Port = 8080
+	// Keep a port chosen with SetRedirectConfig
+	if cfg.Port < config.MinCallbackPort || cfg.Port > config.MaxCallbackPort {
+		cfg.Port = 8080
+	}
+	cfg.RedirectURI = config.RedirectURIForPort(cfg.Port)
 
-	if err := a.config.Save(); err != nil {
+	if err := svc.config.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	// Reinitialize auth service with new credentials
-	authSvc, err := auth.New(a.config)
-	if err != nil {
-		return fmt.Errorf("failed to initialize auth: %w", err)
+	// Recreate auth with the new credentials, along with the services holding the old one
+	a.stopProfileServices()
+	if err := a.startProfileServices(svc.profile, svc.config); err != nil {
+		return err
+	}
+	if a.services().auth == nil {
+		return fmt.Errorf("failed to initialize auth with the new credentials")
+	}
+
+	return nil
+}
+
+// SetRedirectConfig changes the local OAuth callback port (e.g. when 8080 is in use) and
+// restarts the Spotify services with it. The new redirect URI must also be registered in
+// the Spotify app settings; an "auth:redirect-changed" event carries it for the UI to show.
+func (a *App) SetRedirectConfig(port int) error {
+	a.restartMu.Lock()
+	defer a.restartMu.Unlock()
+	svc := a.services()
+	if svc.config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	if port == svc.config.Get().Port {
+		return nil
+	}
+	if err := svc.config.SetCallbackPort(port); err != nil {
+		return err
+	}
+
+	// Recreate auth (and the services holding it) so the callback server listens on the new port
+	a.stopProfileServices()
+	if err := a.startProfileServices(svc.profile, svc.config); err != nil {
+		return err
+	}
+
+	redirectURI := svc.config.Get().RedirectURI
+	log.Printf("OAuth redirect URI changed to %s; add it to your Spotify app's Redirect URIs", redirectURI)
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "auth:redirect-changed", map[string]interface{}{
+			"redirect_uri": redirectURI,
+			"message":      fmt.Sprintf("Add %s to the Redirect URIs in your Spotify app settings", redirectURI),
+		})
+	}
+	return nil
+}
+
+// ExportConfig writes the configuration to path for moving to another machine. Tokens and the
+// client secret are only included when includeSecrets is set.
+func (a *App) ExportConfig(path string, includeSecrets bool) error {
+	svc := a.services()
+	if svc.config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	if err := svc.config.Flush(); err != nil {
+		return err
+	}
+	return svc.config.Export(path, includeSecrets)
+}
+
+// ImportConfig merges a configuration exported with ExportConfig into the active profile and
+// restarts the services so every setting takes effect
+func (a *App) ImportConfig(path string) error {
+	a.restartMu.Lock()
+	defer a.restartMu.Unlock()
+	svc := a.services()
+	if svc.config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	if err := svc.config.Import(path); err != nil {
+		return err
 	}
-	a.auth = authSvc
 
+	a.stopProfileServices()
+	if err := a.startProfileServices(svc.profile, svc.config); err != nil {
+		return err
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "config:imported", path)
+	}
 	return nil
 }
 
@@ -487,11 +1563,18 @@ func (a *App) ValidateCredentials(clientID, clientSecret string) error {
 
 // HasCredentials checks if Spotify credentials are configured
 func (a *App) HasCredentials() bool {
-	cfg := a.config.Get()
+	svc := a.services()
+	if svc.config == nil {
+		return false
+	}
+	cfg := svc.config.Get()
 	return cfg.SpotifyClientID != "" && cfg.SpotifyClientSecret != ""
 }
 
 func main() {
+	// Keep recent log lines for ExportDiagnostics
+	log.SetOutput(io.MultiWriter(os.Stderr, recentLogs))
+
 	// Create an instance of the app structure
 	app := NewApp()
 
@@ -501,6 +1584,8 @@ func main() {
 	if preConfig != nil {
 		cfg := preConfig.Get()
 		disableResizeAtStartup = cfg.Overlay.ResizeLocked
+		// Hand the loaded config to the app so load warnings aren't lost
+		app.active.Store(&profileServices{profile: config.DefaultProfile, config: preConfig})
 	}
 
 	// Create application with options
@@ -520,6 +1605,11 @@ func main() {
 			WindowIsTranslucent:               true,
 			DisableFramelessWindowDecorations: true,
 		},
+		// A second instance would race this one writing config.json; focus this window instead
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               "com.spotly.lyrics-overlay",
+			OnSecondInstanceLaunch: app.onSecondInstanceLaunch,
+		},
 		OnStartup:        app.OnStartup,
 		OnShutdown:       app.OnShutdown,
 		WindowStartState: options.Normal,
@@ -527,7 +1617,7 @@ func main() {
 	})
 
 	if err != nil {
-		fmt.Printf("Error starting application: %v\n", err)
+		log.Printf("Error starting application: %v", err)
 		os.Exit(1)
 	}
 }
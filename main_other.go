@@ -2,7 +2,11 @@
 
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"lyrics-overlay/internal/overlay"
+)
 
 // GetActiveWindow returns the title of the currently active window (stub for non-Windows)
 func (a *App) GetActiveWindow() (string, error) {
@@ -14,6 +18,11 @@ func (a *App) IsOverlayFocused() bool {
 	return false
 }
 
+// monitorBounds is unavailable on non-Windows platforms; Wails only reports screen sizes
+func monitorBounds() ([]overlay.ScreenBounds, error) {
+	return nil, fmt.Errorf("monitor positions not supported on this platform")
+}
+
 // resolveOverlayHWND is a no-op on non-Windows platforms
 func (a *App) resolveOverlayHWND() {
 	// No-op
@@ -24,6 +33,11 @@ func (a *App) setOverlayClickThrough(enable bool) {
 	// No-op
 }
 
+// setOverlayAlpha is a no-op on non-Windows platforms; the frontend applies the opacity
+func (a *App) setOverlayAlpha(opacity float64) {
+	// No-op
+}
+
 // startClickThroughMonitor is a no-op on non-Windows platforms
 func (a *App) startClickThroughMonitor() {
 	// No-op on non-Windows platforms
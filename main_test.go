@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lyrics-overlay/internal/cache"
+	"lyrics-overlay/internal/config"
+	"lyrics-overlay/internal/lyrics"
+	"lyrics-overlay/internal/overlay"
+	"lyrics-overlay/internal/telemetry"
+)
+
+// newCorrectionTestApp returns an App with telemetry on, playing track1 with one recorded lookup
+func newCorrectionTestApp(t *testing.T, lyricsSvc *lyrics.Service) (*App, *telemetry.Service) {
+	dir := t.TempDir()
+	configSvc, err := config.NewWithPath(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatalf("NewWithPath failed: %v", err)
+	}
+	configSvc.Get().Telemetry = true
+	overlaySvc, err := overlay.New(configSvc)
+	if err != nil {
+		t.Fatalf("overlay.New failed: %v", err)
+	}
+	t.Cleanup(overlaySvc.Shutdown)
+	telemetrySvc, err := telemetry.New(filepath.Join(dir, "match_log.json"))
+	if err != nil {
+		t.Fatalf("telemetry.New failed: %v", err)
+	}
+	t.Cleanup(func() { _ = telemetrySvc.Flush() })
+	if err := telemetrySvc.Record(telemetry.Record{TrackKey: telemetry.TrackKey("track1"), Found: true}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	overlaySvc.SetCurrentTrack(&overlay.TrackInfo{ID: "track1", Name: "Song", Artists: []string{"Artist"}, UpdatedAt: time.Now()})
+
+	a := &App{cache: cache.New(10), pinnedPath: filepath.Join(dir, "pinned_lyrics.json")}
+	a.active.Store(&profileServices{config: configSvc, overlay: overlaySvc, lyrics: lyricsSvc, telemetry: telemetrySvc})
+	return a, telemetrySvc
+}
+
+func TestSelectLyricsCandidate_MarksCorrected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/get/2" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 2, "trackName": "Song", "artistName": "Artist", "duration": 200, "syncedLyrics": "[00:01.00]Studio words"}`)
+	}))
+	defer server.Close()
+
+	lyricsSvc := lyrics.NewWithProviders(cache.New(10), lyrics.NewLRCLibProvider(server.Client(), server.URL))
+	a, telemetrySvc := newCorrectionTestApp(t, lyricsSvc)
+
+	if err := a.SelectLyricsCandidate(2); err != nil {
+		t.Fatalf("SelectLyricsCandidate failed: %v", err)
+	}
+	if records := telemetrySvc.Records(); !records[0].Corrected {
+		t.Error("Expected choosing another candidate to mark the lookup corrected")
+	}
+}
+
+func TestPinCurrentLyrics_MarksCorrected(t *testing.T) {
+	a, telemetrySvc := newCorrectionTestApp(t, nil)
+	a.services().overlay.SetLyricsForTrack("track1", &overlay.LyricsData{
+		TrackID: "track1",
+		Lines:   []overlay.LyricsLine{{Timestamp: 1000, Text: "Pinned words"}},
+	})
+
+	if err := a.PinCurrentLyrics(); err != nil {
+		t.Fatalf("PinCurrentLyrics failed: %v", err)
+	}
+	if records := telemetrySvc.Records(); !records[0].Corrected {
+		t.Error("Expected pinning the lyrics to mark the lookup corrected")
+	}
+}
@@ -4,18 +4,27 @@ package main
 
 import (
 	"fmt"
+	"math"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
 	"golang.org/x/sys/windows"
+
+	"lyrics-overlay/internal/overlay"
 )
 
 // Windows constants for extended window styles
 const (
-	_GWL_EXSTYLE       int32 = -20
-	_WS_EX_TRANSPARENT int32 = 0x00000020
-	_WS_EX_LAYERED     int32 = 0x00080000
+	_GWL_EXSTYLE       int32   = -20
+	_WS_EX_TRANSPARENT int32   = 0x00000020
+	_WS_EX_LAYERED     int32   = 0x00080000
+	_LWA_ALPHA         uintptr = 0x00000002
+
+	_MONITOR_DEFAULTTONEAREST uintptr = 0x00000002
+	_MONITORINFOF_PRIMARY     uint32  = 0x00000001
 )
 
 // GetActiveWindow returns the title of the currently active window
@@ -48,6 +57,125 @@ func (a *App) GetActiveWindow() (string, error) {
 	return windows.UTF16ToString(titleBuf), nil
 }
 
+// activeProcessName returns the lowercased executable name (e.g. "cs2.exe") of the foreground window
+func activeProcessName() (string, error) {
+	user32 := windows.NewLazyDLL("user32.dll")
+	procGetForegroundWindow := user32.NewProc("GetForegroundWindow")
+
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return "", fmt.Errorf("no foreground window found")
+	}
+	var pid uint32
+	if _, err := windows.GetWindowThreadProcessId(windows.HWND(hwnd), &pid); err != nil {
+		return "", fmt.Errorf("failed to get window process: %w", err)
+	}
+
+	process, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return "", fmt.Errorf("failed to open process %d: %w", pid, err)
+	}
+	defer windows.CloseHandle(process)
+
+	buf := make([]uint16, windows.MAX_PATH)
+	size := uint32(len(buf))
+	if err := windows.QueryFullProcessImageName(process, 0, &buf[0], &size); err != nil {
+		return "", fmt.Errorf("failed to get process image name: %w", err)
+	}
+	return strings.ToLower(filepath.Base(windows.UTF16ToString(buf[:size]))), nil
+}
+
+// monitorInfo mirrors the Win32 MONITORINFO struct
+type monitorInfo struct {
+	size    uint32
+	monitor windows.Rect
+	work    windows.Rect
+	flags   uint32
+}
+
+var (
+	// Guards enumeratedMonitors, filled by monitorEnumProc during EnumDisplayMonitors
+	monitorEnumMu      sync.Mutex
+	enumeratedMonitors []overlay.ScreenBounds
+	// Created once: Windows callbacks are never freed
+	monitorEnumProc = windows.NewCallback(func(monitor, hdc, rect, data uintptr) uintptr {
+		procGetMonitorInfoW := windows.NewLazyDLL("user32.dll").NewProc("GetMonitorInfoW")
+		info := monitorInfo{size: uint32(unsafe.Sizeof(monitorInfo{}))}
+		if ret, _, _ := procGetMonitorInfoW.Call(monitor, uintptr(unsafe.Pointer(&info))); ret != 0 {
+			enumeratedMonitors = append(enumeratedMonitors, overlay.ScreenBounds{
+				X:       int(info.monitor.Left),
+				Y:       int(info.monitor.Top),
+				Width:   int(info.monitor.Right - info.monitor.Left),
+				Height:  int(info.monitor.Bottom - info.monitor.Top),
+				Primary: info.flags&_MONITORINFOF_PRIMARY != 0,
+			})
+		}
+		return 1 // Continue enumerating
+	})
+)
+
+// monitorBounds returns every monitor's rect in virtual desktop coordinates
+func monitorBounds() ([]overlay.ScreenBounds, error) {
+	procEnumDisplayMonitors := windows.NewLazyDLL("user32.dll").NewProc("EnumDisplayMonitors")
+
+	monitorEnumMu.Lock()
+	defer monitorEnumMu.Unlock()
+	enumeratedMonitors = nil
+	if ret, _, err := procEnumDisplayMonitors.Call(0, 0, monitorEnumProc, 0); ret == 0 {
+		return nil, fmt.Errorf("failed to enumerate monitors: %w", err)
+	}
+	if len(enumeratedMonitors) == 0 {
+		return nil, fmt.Errorf("no monitors found")
+	}
+	return enumeratedMonitors, nil
+}
+
+// foregroundIsFullscreen reports whether the foreground window covers its whole monitor. The
+// desktop and the overlay itself don't count.
+func (a *App) foregroundIsFullscreen() bool {
+	a.clickMu.Lock()
+	overlayHWND := a.overlayHWND
+	a.clickMu.Unlock()
+	hwnd := windows.GetForegroundWindow()
+	if hwnd == 0 || hwnd == windows.GetDesktopWindow() || hwnd == windows.GetShellWindow() || uintptr(hwnd) == overlayHWND {
+		return false
+	}
+	// With the desktop focused, the foreground window is an Explorer background window
+	className := make([]uint16, 64)
+	if n, err := windows.GetClassName(hwnd, &className[0], int32(len(className))); err == nil {
+		switch windows.UTF16ToString(className[:n]) {
+		case "Progman", "WorkerW":
+			return false
+		}
+	}
+
+	user32 := windows.NewLazyDLL("user32.dll")
+	procGetWindowRect := user32.NewProc("GetWindowRect")
+	procMonitorFromWindow := user32.NewProc("MonitorFromWindow")
+	procGetMonitorInfoW := user32.NewProc("GetMonitorInfoW")
+
+	var rect windows.Rect
+	if ret, _, _ := procGetWindowRect.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&rect))); ret == 0 {
+		return false
+	}
+	monitor, _, _ := procMonitorFromWindow.Call(uintptr(hwnd), _MONITOR_DEFAULTTONEAREST)
+	if monitor == 0 {
+		return false
+	}
+	info := monitorInfo{size: uint32(unsafe.Sizeof(monitorInfo{}))}
+	if ret, _, _ := procGetMonitorInfoW.Call(monitor, uintptr(unsafe.Pointer(&info))); ret == 0 {
+		return false
+	}
+
+	screen := overlay.ScreenBounds{
+		X:      int(info.monitor.Left),
+		Y:      int(info.monitor.Top),
+		Width:  int(info.monitor.Right - info.monitor.Left),
+		Height: int(info.monitor.Bottom - info.monitor.Top),
+	}
+	return overlay.CoversScreen(int(rect.Left), int(rect.Top), int(rect.Right-rect.Left), int(rect.Bottom-rect.Top), screen)
+}
+
 // IsOverlayFocused checks if the overlay window is currently focused
 func (a *App) IsOverlayFocused() bool {
 	activeWindow, err := a.GetActiveWindow()
@@ -59,7 +187,8 @@ func (a *App) IsOverlayFocused() bool {
 	return activeWindow == "SpotLy Overlay" || activeWindow == "SpotLy"
 }
 
-// resolveOverlayHWND finds and caches the HWND of the overlay window by its title
+// resolveOverlayHWND finds and caches the HWND of the overlay window by its title. Callers hold
+// clickMu.
 func (a *App) resolveOverlayHWND() {
 	if a.overlayHWND != 0 {
 		return
@@ -100,6 +229,83 @@ func (a *App) setOverlayClickThrough(enable bool) {
 	a.clickThrough = enable
 }
 
+// setOverlayAlpha sets the layered window's alpha to opacity (0-1)
+func (a *App) setOverlayAlpha(opacity float64) {
+	// setOverlayClickThrough edits the same style word, under clickMu
+	a.clickMu.Lock()
+	defer a.clickMu.Unlock()
+	a.resolveOverlayHWND()
+	if a.overlayHWND == 0 {
+		return
+	}
+
+	user32 := windows.NewLazyDLL("user32.dll")
+	procGetWindowLongW := user32.NewProc("GetWindowLongW")
+	procSetWindowLongW := user32.NewProc("SetWindowLongW")
+	procSetLayeredWindowAttributes := user32.NewProc("SetLayeredWindowAttributes")
+
+	// Alpha only applies to layered windows
+	idx := _GWL_EXSTYLE
+	exStyle, _, _ := procGetWindowLongW.Call(a.overlayHWND, uintptr(idx))
+	if int32(exStyle)&_WS_EX_LAYERED == 0 {
+		procSetWindowLongW.Call(a.overlayHWND, uintptr(idx), uintptr(int32(exStyle)|_WS_EX_LAYERED))
+	}
+
+	alpha := uintptr(math.Round(math.Min(math.Max(opacity, 0), 1) * 255))
+	procSetLayeredWindowAttributes.Call(a.overlayHWND, 0, alpha, _LWA_ALPHA)
+}
+
+// matchesAnyApp reports whether the lowercased window title contains any of the app names
+func matchesAnyApp(title string, apps []string) bool {
+	for _, app := range apps {
+		if app = strings.ToLower(strings.TrimSpace(app)); app != "" && strings.Contains(title, app) {
+			return true
+		}
+	}
+	return false
+}
+
+// clickThroughGame identifies a game that needs click-through by its executable, or by window
+// title when the foreground process can't be inspected
+type clickThroughGame struct {
+	title string   // Lowercase window title substring
+	exes  []string // Lowercase executable names
+}
+
+// gamesRequiringClickThrough are the games the overlay becomes unclickable over
+var gamesRequiringClickThrough = []clickThroughGame{
+	{"valorant", []string{"valorant-win64-shipping.exe"}},
+	{"league of legends", []string{"league of legends.exe"}},
+	{"cs2", []string{"cs2.exe"}},
+	{"counter-strike", []string{"cs2.exe", "csgo.exe"}},
+	{"dota 2", []string{"dota2.exe"}},
+	{"overwatch", []string{"overwatch.exe"}},
+	{"apex legends", []string{"r5apex.exe", "r5apex_dx12.exe"}},
+}
+
+// isGameWindow reports whether the foreground window (lowercased title, and executable name
+// if known) is a game needing click-through. The executable is trusted over the title, so a
+// browser tab named after a game doesn't match; blocklisted titles never match.
+func isGameWindow(title, exe string, blocklist []string) bool {
+	if matchesAnyApp(title, blocklist) || (exe != "" && matchesAnyApp(exe, blocklist)) {
+		return false
+	}
+	for _, game := range gamesRequiringClickThrough {
+		if exe == "" {
+			if strings.Contains(title, game.title) {
+				return true
+			}
+			continue
+		}
+		for _, name := range game.exes {
+			if exe == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (a *App) startClickThroughMonitor() {
 	if a.stopClickMonitor != nil {
 		return // already running
@@ -107,17 +313,6 @@ func (a *App) startClickThroughMonitor() {
 
 	a.stopClickMonitor = make(chan struct{})
 
-	// List of games that require click-through (lowercase)
-	gamesRequiringClickThrough := []string{
-		"valorant",
-		"league of legends",
-		"cs2",
-		"counter-strike",
-		"dota 2",
-		"overwatch",
-		"apex legends",
-	}
-
 	go func() {
 		ticker := time.NewTicker(3 * time.Second)
 		defer ticker.Stop()
@@ -125,34 +320,55 @@ func (a *App) startClickThroughMonitor() {
 		for {
 			select {
 			case <-ticker.C:
+				svc := a.services()
 				active, err := a.GetActiveWindow()
 				if err != nil {
 					continue
 				}
 
 				lower := strings.ToLower(active)
-				isInGame := false
 
-				// Check if any game in the list is the active window
-				for _, game := range gamesRequiringClickThrough {
-					if strings.Contains(lower, game) {
-						isInGame = true
-						break
-					}
+				// Fall back to the title alone when the process can't be inspected
+				exe, err := activeProcessName()
+				if err != nil {
+					exe = ""
 				}
+				var blocklist []string
+				if svc.config != nil {
+					blocklist = svc.config.Get().ClickThroughBlocklist
+				}
+				isInGame := isGameWindow(lower, exe, blocklist)
 
 				// Enable click-through (make unclickable) when in game
 				// Disable click-through (make clickable) when not in game
-				if isInGame && !a.clickThrough {
-					a.setOverlayClickThrough(true) // Make unclickable
-				} else if !isInGame && a.clickThrough {
-					a.setOverlayClickThrough(false) // Make clickable
+				// A manual SetClickThrough wins until its grace period ends
+				if !a.clickThroughOverridden() {
+					if isInGame && !a.IsClickThrough() {
+						a.applyClickThrough(true) // Make unclickable
+						a.moveForGame(true)
+					} else if !isInGame && a.IsClickThrough() {
+						a.applyClickThrough(false) // Make clickable
+						a.moveForGame(false)
+					}
+				}
+
+				// Hide entirely for configured apps; auto-hide leaves the user's own toggle alone
+				if svc.overlay != nil && svc.config != nil {
+					svc.overlay.SetAutoHidden(overlay.AutoHideForApp, matchesAnyApp(lower, svc.config.Get().HideForApps))
+					// Games keep the overlay, made click-through above
+					svc.overlay.SetAutoHidden(overlay.AutoHideFullscreen, svc.config.Get().HideOnFullscreen && !isInGame && a.foregroundIsFullscreen())
 				}
 
 			case <-a.stopClickMonitor:
+				svc := a.services()
 				// Ensure click-through is disabled on shutdown so overlay is clickable
-				if a.clickThrough {
-					a.setOverlayClickThrough(false)
+				if a.IsClickThrough() {
+					a.applyClickThrough(false)
+				}
+				a.moveForGame(false)
+				if svc.overlay != nil {
+					svc.overlay.SetAutoHidden(overlay.AutoHideForApp, false)
+					svc.overlay.SetAutoHidden(overlay.AutoHideFullscreen, false)
 				}
 				return
 			}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"lyrics-overlay/internal/config"
+)
+
+// opacityFrame is the interval between opacity animation steps (~60 fps)
+const opacityFrame = 16 * time.Millisecond
+
+// SetOpacityAnimated fades the overlay opacity to target over durationMs, saving each step to
+// the config. A later opacity change (animated or via UpdateOverlayConfig) cancels the fade.
+func (a *App) SetOpacityAnimated(target float64, durationMs int) error {
+	svc := a.services()
+	if svc.overlay == nil {
+		return fmt.Errorf("overlay service not available")
+	}
+	target = math.Min(math.Max(target, config.MinOpacity), config.MaxOpacity)
+
+	a.opacityMu.Lock()
+	defer a.opacityMu.Unlock()
+	a.cancelOpacityAnimationLocked()
+
+	start := svc.overlay.GetOverlayConfig().Opacity
+	if durationMs <= 0 || start == target {
+		return a.applyOpacity(target)
+	}
+
+	stop := make(chan struct{})
+	a.opacityStop = stop
+	go a.animateOpacity(start, target, time.Duration(durationMs)*time.Millisecond, stop)
+	return nil
+}
+
+// animateOpacity steps the opacity linearly from start to target until done or stopped
+func (a *App) animateOpacity(start, target float64, duration time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(opacityFrame)
+	defer ticker.Stop()
+	began := time.Now()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		fraction := math.Min(float64(time.Since(began))/float64(duration), 1)
+		a.opacityMu.Lock()
+		if a.opacityStop != stop {
+			a.opacityMu.Unlock()
+			return // Superseded between ticks
+		}
+		if err := a.applyOpacity(start + (target-start)*fraction); err != nil {
+			log.Printf("Failed to update overlay opacity: %v", err)
+		}
+		if fraction >= 1 {
+			a.opacityStop = nil
+			a.opacityMu.Unlock()
+			return
+		}
+		a.opacityMu.Unlock()
+	}
+}
+
+// cancelOpacityAnimation stops a running fade, leaving the opacity where it got to
+func (a *App) cancelOpacityAnimation() {
+	a.opacityMu.Lock()
+	defer a.opacityMu.Unlock()
+	a.cancelOpacityAnimationLocked()
+}
+
+// cancelOpacityAnimationLocked stops a running fade (must hold opacityMu)
+func (a *App) cancelOpacityAnimationLocked() {
+	if a.opacityStop != nil {
+		close(a.opacityStop)
+		a.opacityStop = nil
+	}
+}
+
+// applyOpacity saves the opacity, sets the window alpha where supported and tells the frontend
+func (a *App) applyOpacity(opacity float64) error {
+	svc := a.services()
+	overlayConfig := svc.overlay.GetOverlayConfig()
+	overlayConfig.Opacity = opacity
+	if err := svc.overlay.UpdateOverlayConfig(overlayConfig); err != nil {
+		return err
+	}
+	a.setOverlayAlpha(opacity)
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "overlay:opacity", opacity)
+	}
+	return nil
+}